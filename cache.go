@@ -0,0 +1,121 @@
+package letscloud
+
+import (
+	"sync"
+	"time"
+)
+
+// CacheConfig sets how long the responses of the catalog endpoints are cached.
+// A zero duration disables caching for that endpoint.
+type CacheConfig struct {
+	Locations      time.Duration
+	LocationPlans  time.Duration
+	LocationImages time.Duration
+}
+
+// DefaultCacheConfig caches the location, plan and image catalogs for an hour
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Locations:      time.Hour,
+		LocationPlans:  time.Hour,
+		LocationImages: time.Hour,
+	}
+}
+
+func (cfg CacheConfig) ttl(op string) time.Duration {
+	switch op {
+	case "Locations":
+		return cfg.Locations
+	case "LocationPlans":
+		return cfg.LocationPlans
+	case "LocationImages":
+		return cfg.LocationImages
+	}
+
+	return 0
+}
+
+// WithCache caches the responses of Locations, LocationPlans and LocationImages
+// in memory. Expired entries carrying an ETag are revalidated with If-None-Match.
+func WithCache(cfg CacheConfig) Option {
+	return func(lc *LetsCloud) {
+		lc.cache = newResponseCache(cfg)
+	}
+}
+
+// InvalidateCache drops the cached responses of the given operations, e.g.
+// "LocationPlans", or every cached response when none is given
+func (c *LetsCloud) InvalidateCache(ops ...string) {
+	if c.cache != nil {
+		c.cache.invalidate(ops...)
+	}
+}
+
+// responseCache holds response bodies keyed by operation and endpoint.
+// It is safe for concurrent use.
+type responseCache struct {
+	mu      sync.RWMutex
+	cfg     CacheConfig
+	entries map[cacheKey]*cacheEntry
+}
+
+type cacheKey struct {
+	op       string
+	endpoint string
+}
+
+// cacheEntry is never modified once stored, so it can be read without holding the lock
+type cacheEntry struct {
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+func newResponseCache(cfg CacheConfig) *responseCache {
+	return &responseCache{cfg: cfg, entries: make(map[cacheKey]*cacheEntry)}
+}
+
+// get returns the entry stored for the endpoint, if any, and whether it is still fresh
+func (rc *responseCache) get(op, endpoint string) (*cacheEntry, bool) {
+	rc.mu.RLock()
+	e, ok := rc.entries[cacheKey{op: op, endpoint: endpoint}]
+	rc.mu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return e, time.Now().Before(e.expires)
+}
+
+// set stores body for the endpoint, keeping the previous ETag when etag is empty
+func (rc *responseCache) set(op, endpoint string, body []byte, etag string) {
+	key := cacheKey{op: op, endpoint: endpoint}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if prev, ok := rc.entries[key]; ok && etag == "" {
+		etag = prev.etag
+	}
+
+	rc.entries[key] = &cacheEntry{body: body, etag: etag, expires: time.Now().Add(rc.cfg.ttl(op))}
+}
+
+func (rc *responseCache) invalidate(ops ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if len(ops) == 0 {
+		rc.entries = make(map[cacheKey]*cacheEntry)
+		return
+	}
+
+	for key := range rc.entries {
+		for _, op := range ops {
+			if key.op == op {
+				delete(rc.entries, key)
+			}
+		}
+	}
+}
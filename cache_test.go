@@ -0,0 +1,73 @@
+package letscloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	var requests, notModified int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"success": true, "data": [{"slug": "MIA1", "city": "Miami"}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithCache(CacheConfig{Locations: 50 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Locations(); err != nil {
+				t.Errorf("Locations() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	first := atomic.LoadInt32(&requests)
+	if _, err := c.Locations(); err != nil {
+		t.Fatalf("Locations() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != first {
+		t.Errorf("fresh cache entry sent %d requests, want none", got-first)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	locations, err := c.Locations()
+	if err != nil {
+		t.Fatalf("Locations() error = %v", err)
+	}
+	if len(locations) != 1 || locations[0].Slug != "MIA1" {
+		t.Errorf("Locations() after revalidation = %v, want cached locations", locations)
+	}
+	if atomic.LoadInt32(&notModified) != 1 {
+		t.Errorf("expired entry was not revalidated with If-None-Match")
+	}
+
+	c.InvalidateCache("Locations")
+
+	before := atomic.LoadInt32(&requests)
+	if _, err := c.Locations(); err != nil {
+		t.Fatalf("Locations() error = %v", err)
+	}
+	if atomic.LoadInt32(&requests) != before+1 || atomic.LoadInt32(&notModified) != 1 {
+		t.Errorf("invalidated entry was not fetched again without revalidation")
+	}
+}
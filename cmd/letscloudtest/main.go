@@ -0,0 +1,33 @@
+// Command letscloudtest serves the fake LetsCloud API of the letscloudtest
+// package, for tests written in other languages or run against a binary:
+//
+//	letscloudtest -addr :8080 -api-key secret
+//	LETSCLOUD_API_KEY=secret mytool --base-url http://localhost:8080
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/letscloudtest"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	apiKey := flag.String("api-key", letscloudtest.DefaultAPIKey, "API key expected in the api-token header, empty accepts any key")
+	build := flag.Duration("build-time", 2*time.Second, "time a new instance spends building")
+	boot := flag.Duration("boot-time", 2*time.Second, "time a built instance spends booting")
+	operation := flag.Duration("operation-time", time.Second, "time an instance stays locked by a power, reboot or password operation")
+	snapshot := flag.Duration("snapshot-time", 3*time.Second, "time a snapshot takes to build")
+	flag.Parse()
+
+	srv := letscloudtest.New(
+		letscloudtest.WithAPIKey(*apiKey),
+		letscloudtest.WithBuildTime(*build, *boot),
+		letscloudtest.WithOperationTime(*operation, *snapshot))
+
+	log.Printf("letscloudtest: serving the fake LetsCloud API on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, srv))
+}
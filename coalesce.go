@@ -0,0 +1,93 @@
+package letscloud
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// WithRequestCoalescing collapses identical GET calls in flight at the same
+// time, e.g. many goroutines calling Instance with the same identifier, into a
+// single request. Its response body is buffered and decoded by every caller
+// into a value of its own. Calls carrying headers set with WithHeader are never
+// coalesced.
+func WithRequestCoalescing(enabled bool) Option {
+	return func(lc *LetsCloud) {
+		lc.flight = nil
+		if enabled {
+			lc.flight = &flightGroup{}
+		}
+	}
+}
+
+// flightGroup runs a single call at a time per key, sharing its response body
+// with the identical calls made meanwhile
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	body []byte
+	meta *ResponseMeta
+	err  error
+}
+
+// do fetches the response body of the call identified by key with fn, which
+// also decodes it into out, unless an identical call is in flight. In that
+// case, once it completes, its body is decoded into out and a copy of its
+// response metadata is stored in meta. A call waiting for another one stops
+// when its own ctx is done, and runs fn itself when the other call failed
+// because its context was done.
+func (g *flightGroup) do(ctx context.Context, key string, out interface{}, meta *ResponseMeta, fn func() ([]byte, error)) error {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-call.done:
+		}
+
+		if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+			_, err := fn()
+			return err
+		}
+
+		if meta != nil && call.meta != nil {
+			*meta = *call.meta.clone()
+			meta.Shared = true
+		}
+
+		if call.err != nil {
+			return call.err
+		}
+
+		return decodeResponse(call.body, out)
+	}
+
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		if meta != nil {
+			call.meta = meta.clone()
+		}
+		close(call.done)
+	}()
+
+	call.body, call.err = fn()
+
+	return call.err
+}
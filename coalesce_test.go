@@ -0,0 +1,198 @@
+package letscloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowAPI serves instances once release is closed, counting the requests received
+func slowAPI(t *testing.T, release <-chan struct{}, hits *int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		<-release
+		id := strings.TrimPrefix(r.URL.Path, "/instances/")
+		w.Write([]byte(`{"success": true, "data": {"identifier": "` + id + `", "hostname": "host-` + id + `"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestClient_RequestCoalescing(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		ids        []string
+		wantHits   int32
+		wantShared int
+	}{
+		{name: "identical calls", enabled: true, ids: []string{"a", "a", "a", "a"}, wantHits: 1, wantShared: 3},
+		{name: "different identifiers", enabled: true, ids: []string{"a", "b", "a", "b"}, wantHits: 2, wantShared: 2},
+		{name: "disabled", ids: []string{"a", "a", "a", "a"}, wantHits: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			var hits int32
+			srv := slowAPI(t, release, &hits)
+
+			c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithRequestCoalescing(tt.enabled))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var wg sync.WaitGroup
+			metas := make([]ResponseMeta, len(tt.ids))
+			errs := make([]error, len(tt.ids))
+			hostnames := make([]string, len(tt.ids))
+			for i, id := range tt.ids {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					inst, err := c.Instance(id, WithResponseMeta(&metas[i]))
+					errs[i] = err
+					if err == nil {
+						hostnames[i] = inst.Hostname
+					}
+				}()
+			}
+
+			// let every call join the ones in flight before answering
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			shared := 0
+			for i, id := range tt.ids {
+				if errs[i] != nil {
+					t.Fatalf("Instance(%q) error = %v", id, errs[i])
+				}
+				if hostnames[i] != "host-"+id {
+					t.Errorf("Instance(%q) hostname = %q, want %q", id, hostnames[i], "host-"+id)
+				}
+				if metas[i].StatusCode != http.StatusOK {
+					t.Errorf("Instance(%q) status = %d, want 200", id, metas[i].StatusCode)
+				}
+				if metas[i].Shared {
+					shared++
+				}
+			}
+
+			if got := atomic.LoadInt32(&hits); got != tt.wantHits {
+				t.Errorf("requests sent = %d, want %d", got, tt.wantHits)
+			}
+			if shared != tt.wantShared {
+				t.Errorf("shared responses = %d, want %d", shared, tt.wantShared)
+			}
+		})
+	}
+}
+
+func TestClient_RequestCoalescingCanceled(t *testing.T) {
+	release := make(chan struct{})
+	var hits int32
+	srv := slowAPI(t, release, &hits)
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithRequestCoalescing(true))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := c.InstanceContext(ctx, "a")
+		leader <- err
+	}()
+
+	for atomic.LoadInt32(&hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	follower := make(chan error, 1)
+	go func() {
+		_, err := c.Instance("a")
+		follower <- err
+	}()
+
+	// the call that joined the canceled one sends a request of its own
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled call error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if err := <-follower; err != nil {
+		t.Errorf("joined call error = %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("requests sent = %d, want 2", got)
+	}
+}
+
+func TestClient_RequestCoalescingPrivateResults(t *testing.T) {
+	release := make(chan struct{})
+	var hits int32
+	srv := slowAPI(t, release, &hits)
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithRequestCoalescing(true))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	const callers = 20
+
+	var wg sync.WaitGroup
+	labels := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var meta ResponseMeta
+			inst, err := c.Instance("abc", WithResponseMeta(&meta))
+			if err != nil {
+				t.Errorf("Instance() error = %v", err)
+				return
+			}
+			// every caller owns its result and may change it
+			inst.Label = fmt.Sprintf("caller-%d", i)
+			meta.Header.Set("X-Caller", inst.Label)
+			labels[i] = inst.Label
+		}()
+		go func() {
+			defer wg.Done()
+			var out struct {
+				Identifier string `json:"identifier"`
+			}
+			if err := c.Do(context.Background(), http.MethodGet, "/instances/abc", nil, &out); err != nil || out.Identifier != "abc" {
+				t.Errorf("Do() = %+v, %v, want the instance", out, err)
+			}
+			out.Identifier = "changed"
+		}()
+	}
+
+	// let every call join the ones in flight before answering
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("requests sent = %d, want 1", got)
+	}
+	for i, label := range labels {
+		if label != fmt.Sprintf("caller-%d", i) {
+			t.Errorf("caller %d label = %q", i, label)
+		}
+	}
+}
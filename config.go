@@ -0,0 +1,202 @@
+package letscloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+const (
+	// EnvProfile selects the profile used by NewFromProfile when no name is given
+	EnvProfile = "LETSCLOUD_PROFILE"
+	// EnvConfigFile overrides the path of the config file read by NewFromProfile
+	EnvConfigFile = "LETSCLOUD_CONFIG_FILE"
+)
+
+// ConfigProfile holds the settings of a named profile of the config file:
+//
+//	[staging]
+//	api_key_env = STAGING_LETSCLOUD_KEY
+//	base_url = https://staging.example.com/api
+//	timeout = 30s
+//	debug = true
+//	retry_max_attempts = 4
+//	retry_base_delay = 500ms
+//	retry_max_delay = 30s
+//
+// The API key is given either directly with api_key, or by reference with
+// api_key_env or api_key_command. Without any of them the api_key of the
+// same profile in the credentials file is used.
+type ConfigProfile struct {
+	Name          string
+	APIKey        string
+	APIKeyEnv     string
+	APIKeyCommand string
+	BaseURL       string
+	Timeout       time.Duration
+	Debug         bool
+	Retry         *httpclient.RetryPolicy
+}
+
+// DefaultConfigFile returns the path of the config file, $LETSCLOUD_CONFIG_FILE,
+// $XDG_CONFIG_HOME/letscloud/config or ~/.config/letscloud/config
+func DefaultConfigFile() string {
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return path
+	}
+
+	return configPath("config")
+}
+
+// LoadConfigProfile reads the named profile from the config file at path
+func LoadConfigProfile(path, name string) (*ConfigProfile, error) {
+	ini, err := readINI(path)
+	if err != nil {
+		return nil, err
+	}
+
+	section, ok := ini[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s in %s", ErrProfileNotFound, name, path)
+	}
+
+	p := &ConfigProfile{
+		Name:          name,
+		APIKey:        section["api_key"],
+		APIKeyEnv:     section["api_key_env"],
+		APIKeyCommand: section["api_key_command"],
+		BaseURL:       section["base_url"],
+	}
+
+	if v, ok := section["timeout"]; ok {
+		if p.Timeout, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("profile %s: timeout: %w", name, err)
+		}
+	}
+
+	if v, ok := section["debug"]; ok {
+		if p.Debug, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("profile %s: debug: %w", name, err)
+		}
+	}
+
+	if p.Retry, err = parseRetryPolicy(section); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+
+	return p, nil
+}
+
+// parseRetryPolicy builds a retry policy from the retry_* keys, starting from the default policy
+func parseRetryPolicy(section map[string]string) (*httpclient.RetryPolicy, error) {
+	var found bool
+	policy := httpclient.DefaultRetryPolicy()
+
+	if v, ok := section["retry_max_attempts"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("retry_max_attempts: %w", err)
+		}
+		policy.MaxAttempts, found = n, true
+	}
+
+	for key, d := range map[string]*time.Duration{
+		"retry_base_delay": &policy.BaseDelay,
+		"retry_max_delay":  &policy.MaxDelay,
+	} {
+		if v, ok := section[key]; ok {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			*d, found = parsed, true
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return &policy, nil
+}
+
+// Options returns the options applying the profile settings
+func (p *ConfigProfile) Options() []Option {
+	var opts []Option
+
+	if p.BaseURL != "" {
+		opts = append(opts, WithBaseURL(p.BaseURL))
+	}
+
+	if p.Timeout > 0 {
+		opts = append(opts, WithTimeout(p.Timeout))
+	}
+
+	if p.Debug {
+		opts = append(opts, WithDebug(true))
+	}
+
+	if p.Retry != nil {
+		opts = append(opts, WithRetryPolicy(*p.Retry))
+	}
+
+	return opts
+}
+
+// Credentials returns the provider resolving the API key of the profile
+func (p *ConfigProfile) Credentials() CredentialsProvider {
+	providers := []CredentialsProvider{StaticCredentials(p.APIKey)}
+
+	if p.APIKeyEnv != "" {
+		providers = append(providers, EnvVarCredentials(p.APIKeyEnv))
+	}
+
+	if fields := strings.Fields(p.APIKeyCommand); len(fields) > 0 {
+		providers = append(providers, CommandCredentials(fields[0], fields[1:]...))
+	}
+
+	return ChainCredentials(append(providers, FileCredentials("", p.Name))...)
+}
+
+// NewFromProfile creates a client configured by the named profile of the config
+// file. An empty name uses $LETSCLOUD_PROFILE, then "default". opts are applied
+// after the profile settings, so they take precedence. It fails with
+// ErrInvalidToken when the API key of the profile cannot be found, rather than
+// falling back to the keys of DefaultCredentialsChain.
+func NewFromProfile(name string, opts ...Option) (*LetsCloud, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+
+	if name == "" {
+		name = "default"
+	}
+
+	p, err := LoadConfigProfile(DefaultConfigFile(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := p.Credentials().Retrieve(context.Background())
+	if errors.Is(err, ErrNoCredentials) {
+		return nil, fmt.Errorf("%w: %w for profile %s", ErrInvalidToken, ErrNoCredentials, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := New(creds.APIKey, append(p.Options(), opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.credentialsSource = fmt.Sprintf("profile:%s %s", name, creds.Source)
+
+	return c, nil
+}
@@ -0,0 +1,117 @@
+package letscloud
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewFromProfile(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("api-token") != "staging-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"success": true, "data": {"name": "Staging"}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	config := `
+[default]
+api_key = default-key
+
+[staging]
+api_key_env = STAGING_LETSCLOUD_KEY
+base_url = ` + srv.URL + `
+timeout = 5s
+retry_max_attempts = 2
+retry_base_delay = 1ms
+`
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvConfigFile, path)
+	t.Setenv(EnvProfile, "staging")
+	t.Setenv("STAGING_LETSCLOUD_KEY", "staging-key")
+	t.Setenv(EnvAPIKey, "")
+
+	c, err := NewFromProfile("")
+	if err != nil {
+		t.Fatalf("NewFromProfile() error = %v", err)
+	}
+
+	if want := "profile:staging env:STAGING_LETSCLOUD_KEY"; c.CredentialsSource() != want {
+		t.Errorf("CredentialsSource() = %q, want %q", c.CredentialsSource(), want)
+	}
+
+	profile, err := c.Profile()
+	if err != nil {
+		t.Fatalf("Profile() error = %v", err)
+	}
+	if profile.Name != "Staging" || attempts != 2 {
+		t.Errorf("Profile() = %v after %d attempts, want Staging after a retry", profile, attempts)
+	}
+
+	if _, err := NewFromProfile("missing"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("NewFromProfile() error = %v, want %v", err, ErrProfileNotFound)
+	}
+}
+
+func TestNewFromProfile_MissingKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	config := `
+[staging]
+api_key_env = STAGING_LETSCLOUD_KEY
+`
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvConfigFile, path)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("STAGING_LETSCLOUD_KEY", "")
+	t.Setenv(EnvAPIKey, "production-key")
+
+	// the default credentials must not be used in place of the key of the profile
+	c, err := NewFromProfile("staging")
+	if !errors.Is(err, ErrInvalidToken) || !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("NewFromProfile() error = %v, want %v", err, ErrInvalidToken)
+	}
+	if c != nil {
+		t.Errorf("NewFromProfile() client with credentials from %s, want none", c.CredentialsSource())
+	}
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	config := "[prod]\napi_key_command = pass show letscloud\ntimeout = 1m\ndebug = true\n\n[broken]\ntimeout = soon\n"
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadConfigProfile(path, "prod")
+	if err != nil {
+		t.Fatalf("LoadConfigProfile() error = %v", err)
+	}
+	if p.APIKeyCommand != "pass show letscloud" || p.Timeout != time.Minute || !p.Debug || p.Retry != nil {
+		t.Errorf("LoadConfigProfile() = %+v", p)
+	}
+
+	if _, err := LoadConfigProfile(path, "broken"); err == nil {
+		t.Errorf("LoadConfigProfile() error = nil, want invalid timeout")
+	}
+}
@@ -0,0 +1,223 @@
+package letscloud
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EnvAPIKey is the environment variable read by EnvCredentials
+const EnvAPIKey = "LETSCLOUD_API_KEY"
+
+// ErrNoCredentials is returned by a CredentialsProvider that has no API key to offer
+var ErrNoCredentials = errors.New("error no credentials found")
+
+// Credentials holds an API key along with where it was found
+type Credentials struct {
+	APIKey string
+	// Source describes the provider the key came from, e.g. "env:LETSCLOUD_API_KEY"
+	Source string
+}
+
+// CredentialsProvider supplies the API key used by the client
+type CredentialsProvider interface {
+	// Retrieve returns the credentials, or an error wrapping ErrNoCredentials
+	// when the provider has none so the next provider of a chain is tried
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc adapts a callback to the CredentialsProvider interface
+type CredentialsFunc func(ctx context.Context) (string, error)
+
+// Retrieve calls f
+func (f CredentialsFunc) Retrieve(ctx context.Context) (Credentials, error) {
+	key, err := f(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	if key == "" {
+		return Credentials{}, ErrNoCredentials
+	}
+
+	return Credentials{APIKey: key, Source: "func"}, nil
+}
+
+type staticCredentials string
+
+// StaticCredentials provides the given API key
+func StaticCredentials(apiKey string) CredentialsProvider {
+	return staticCredentials(apiKey)
+}
+
+func (s staticCredentials) Retrieve(context.Context) (Credentials, error) {
+	if s == "" {
+		return Credentials{}, ErrNoCredentials
+	}
+
+	return Credentials{APIKey: string(s), Source: "static"}, nil
+}
+
+type envCredentials string
+
+// EnvCredentials provides the API key found in the LETSCLOUD_API_KEY environment variable
+func EnvCredentials() CredentialsProvider {
+	return envCredentials(EnvAPIKey)
+}
+
+// EnvVarCredentials provides the API key found in the given environment variable
+func EnvVarCredentials(name string) CredentialsProvider {
+	return envCredentials(name)
+}
+
+func (e envCredentials) Retrieve(context.Context) (Credentials, error) {
+	key := os.Getenv(string(e))
+	if key == "" {
+		return Credentials{}, ErrNoCredentials
+	}
+
+	return Credentials{APIKey: key, Source: "env:" + string(e)}, nil
+}
+
+type fileCredentials struct {
+	path    string
+	profile string
+}
+
+// FileCredentials provides the api_key of the given profile section of an INI
+// credentials file. An empty path uses DefaultCredentialsFile and an empty
+// profile uses "default":
+//
+//	[default]
+//	api_key = your-api-key
+func FileCredentials(path, profile string) CredentialsProvider {
+	return fileCredentials{path: path, profile: profile}
+}
+
+// DefaultCredentialsFile returns the path of the credentials file,
+// $XDG_CONFIG_HOME/letscloud/credentials or ~/.config/letscloud/credentials
+func DefaultCredentialsFile() string {
+	return configPath("credentials")
+}
+
+func configPath(name string) string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "letscloud", name)
+}
+
+func (f fileCredentials) Retrieve(context.Context) (Credentials, error) {
+	path := f.path
+	if path == "" {
+		path = DefaultCredentialsFile()
+	}
+
+	profile := f.profile
+	if profile == "" {
+		profile = "default"
+	}
+
+	ini, err := readINI(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Credentials{}, fmt.Errorf("%w in %s", ErrNoCredentials, path)
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	key := ini[profile]["api_key"]
+	if key == "" {
+		return Credentials{}, fmt.Errorf("%w for profile %s in %s", ErrNoCredentials, profile, path)
+	}
+
+	return Credentials{APIKey: key, Source: "file:" + path + "[" + profile + "]"}, nil
+}
+
+type commandCredentials struct {
+	name string
+	args []string
+}
+
+// CommandCredentials provides the API key printed on stdout by the given command,
+// e.g. a password manager CLI
+func CommandCredentials(name string, args ...string) CredentialsProvider {
+	return commandCredentials{name: name, args: args}
+}
+
+func (c commandCredentials) Retrieve(ctx context.Context) (Credentials, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return Credentials{}, fmt.Errorf("credentials command %s: %w: %s", c.name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return Credentials{}, fmt.Errorf("%w from command %s", ErrNoCredentials, c.name)
+	}
+
+	return Credentials{APIKey: key, Source: "command:" + c.name}, nil
+}
+
+type chainCredentials []CredentialsProvider
+
+// ChainCredentials tries each provider in order and returns the first credentials found
+func ChainCredentials(providers ...CredentialsProvider) CredentialsProvider {
+	return chainCredentials(providers)
+}
+
+func (c chainCredentials) Retrieve(ctx context.Context) (Credentials, error) {
+	for _, p := range c {
+		creds, err := p.Retrieve(ctx)
+		if err == nil {
+			return creds, nil
+		}
+
+		if !errors.Is(err, ErrNoCredentials) {
+			return Credentials{}, err
+		}
+	}
+
+	return Credentials{}, ErrNoCredentials
+}
+
+// DefaultCredentialsChain looks for the API key in the following order: the explicit
+// key, the LETSCLOUD_API_KEY environment variable, the default credentials file and
+// finally the extra providers, such as a CommandCredentials or CredentialsFunc
+func DefaultCredentialsChain(apiKey string, extra ...CredentialsProvider) CredentialsProvider {
+	providers := []CredentialsProvider{
+		StaticCredentials(apiKey),
+		EnvCredentials(),
+		FileCredentials("", ""),
+	}
+
+	return ChainCredentials(append(providers, extra...)...)
+}
+
+// WithCredentials appends providers to the default credentials chain used by New
+func WithCredentials(providers ...CredentialsProvider) Option {
+	return func(lc *LetsCloud) {
+		lc.credentials = append(lc.credentials, providers...)
+	}
+}
+
+// CredentialsSource reports where the API key in use was found, e.g. "env:LETSCLOUD_API_KEY"
+func (c *LetsCloud) CredentialsSource() string {
+	return c.credentialsSource
+}
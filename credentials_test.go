@@ -0,0 +1,107 @@
+package letscloud
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultCredentialsChain(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	writeCredentials := func(content string) {
+		path := filepath.Join(dir, "letscloud", "credentials")
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	callback := CredentialsFunc(func(context.Context) (string, error) {
+		return "callback-key", nil
+	})
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		env        string
+		file       string
+		extra      []CredentialsProvider
+		wantKey    string
+		wantSource string
+		wantErr    error
+	}{
+		{
+			name:       "explicit key wins",
+			apiKey:     "explicit-key",
+			env:        "env-key",
+			wantKey:    "explicit-key",
+			wantSource: "static",
+		},
+		{
+			name:       "environment variable",
+			env:        "env-key",
+			file:       "[default]\napi_key = file-key\n",
+			wantKey:    "env-key",
+			wantSource: "env:LETSCLOUD_API_KEY",
+		},
+		{
+			name:       "credentials file",
+			file:       "# comment\n[default]\napi_key = \"file-key\"\n",
+			wantKey:    "file-key",
+			wantSource: "file:" + filepath.Join(dir, "letscloud", "credentials") + "[default]",
+		},
+		{
+			name:       "command",
+			extra:      []CredentialsProvider{CommandCredentials("echo", "command-key"), callback},
+			wantKey:    "command-key",
+			wantSource: "command:echo",
+		},
+		{
+			name:       "callback",
+			extra:      []CredentialsProvider{callback},
+			wantKey:    "callback-key",
+			wantSource: "func",
+		},
+		{
+			name:    "nothing found",
+			wantErr: ErrNoCredentials,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvAPIKey, tt.env)
+			os.RemoveAll(filepath.Join(dir, "letscloud"))
+			if tt.file != "" {
+				writeCredentials(tt.file)
+			}
+
+			got, err := DefaultCredentialsChain(tt.apiKey, tt.extra...).Retrieve(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Retrieve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.APIKey != tt.wantKey || got.Source != tt.wantSource {
+				t.Errorf("Retrieve() = %+v, want key %q from %q", got, tt.wantKey, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestNew_Credentials(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvAPIKey, "env-key")
+
+	c, err := New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if c.APIKey() != "env-key" || c.CredentialsSource() != "env:LETSCLOUD_API_KEY" {
+		t.Errorf("New() key = %q from %q, want env-key from the environment", c.APIKey(), c.CredentialsSource())
+	}
+}
@@ -0,0 +1,169 @@
+package letscloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// decodeStream decodes the response read from r into out. The elements of a
+// list in the data field are decoded one at a time, so the body is never held
+// in memory as a whole. This costs time rather than saving it, as the decoder
+// scans every element before unmarshaling it.
+func decodeStream(r io.Reader, out interface{}) error {
+	dec := json.NewDecoder(r)
+
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return dec.Decode(out)
+	}
+	v = v.Elem()
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		field, ok := fieldByName(v, key)
+		switch {
+		case !ok:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		case key == "data" && field.Kind() == reflect.Slice:
+			err = decodeList(dec, field)
+		default:
+			err = dec.Decode(field.Addr().Interface())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// decodeList decodes a JSON array into the slice field, one element at a time
+func decodeList(dec *json.Decoder, field reflect.Value) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("json: cannot unmarshal %v into Go value of type %s", tok, field.Type())
+	}
+
+	// every element is decoded in place, at the end of the slice
+	field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+	for n := 0; dec.More(); n++ {
+		if n == field.Cap() {
+			field.Grow(1)
+		}
+		field.SetLen(n + 1)
+		if err := dec.Decode(field.Index(n).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("json: expected %v, found %v", want, tok)
+	}
+
+	return nil
+}
+
+// fieldByName returns the field of the struct v decoded from the JSON key,
+// looking into embedded structs such as domains.CommonResponse the way
+// encoding/json does, an exact match of the name taking precedence
+func fieldByName(v reflect.Value, key string) (reflect.Value, bool) {
+	var folded reflect.Value
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			if f, ok := fieldByName(v.Field(i), key); ok {
+				return f, true
+			}
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		if name == key {
+			return v.Field(i), true
+		}
+		if !folded.IsValid() && strings.EqualFold(name, key) {
+			folded = v.Field(i)
+		}
+	}
+
+	return folded, folded.IsValid()
+}
+
+// newTarget returns a zero value of the type out points to, so each attempt
+// decodes into a fresh value and a failed one leaves out untouched. The data
+// decoded by Do gets a fresh value of its own as well.
+func newTarget(out interface{}) interface{} {
+	if raw, ok := out.(*rawResponse); ok {
+		fresh := &rawResponse{Data: raw.Data}
+		if isPointer(raw.Data) {
+			fresh.Data = reflect.New(reflect.TypeOf(raw.Data).Elem()).Interface()
+		}
+		return fresh
+	}
+
+	return reflect.New(reflect.TypeOf(out).Elem()).Interface()
+}
+
+// setTarget copies the value decoded by newTarget into out
+func setTarget(out, fresh interface{}) {
+	if raw, ok := out.(*rawResponse); ok {
+		decoded := fresh.(*rawResponse)
+		raw.CommonResponse = decoded.CommonResponse
+		if isPointer(raw.Data) {
+			reflect.ValueOf(raw.Data).Elem().Set(reflect.ValueOf(decoded.Data).Elem())
+		}
+		return
+	}
+
+	reflect.ValueOf(out).Elem().Set(reflect.ValueOf(fresh).Elem())
+}
+
+// isPointer reports whether v is a non-nil pointer
+func isPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && !rv.IsNil()
+}
@@ -0,0 +1,278 @@
+package letscloud
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/domains"
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+// gzipServer serves body, compressed when the request accepts gzip
+func gzipServer(body []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write(body)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write(body)
+		zw.Close()
+	}))
+}
+
+func TestClient_GzipResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		want     int
+		wantErr  error
+		apiError bool
+	}{
+		{
+			name: "instances",
+			body: `{"success": true, "data": [{"identifier": "a"}, {"identifier": "b"}]}`,
+			want: 2,
+		},
+		{
+			name:     "rejected request",
+			body:     `{"success": false, "message": "Account suspended"}`,
+			apiError: true,
+		},
+		{
+			name:    "malformed body",
+			body:    `{"success": true, "data": [`,
+			wantErr: ErrDecodingResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := gzipServer([]byte(tt.body))
+			defer srv.Close()
+
+			c, err := New(TEST_API_KEY, WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := c.Instances()
+
+			var apiErr *APIError
+			if tt.apiError {
+				if !errors.As(err, &apiErr) || apiErr.Message != "Account suspended" {
+					t.Fatalf("Instances() error = %v, want an APIError", err)
+				}
+			} else if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Instances() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Instances() = %d instances, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func instancesPayload(n int) []byte {
+	resp := domains.GetInstancesResponse{}
+	resp.Success = true
+	for i := 0; i < n; i++ {
+		resp.Data = append(resp.Data, domains.Instance{
+			Identifier:    fmt.Sprintf("inst%06d", i),
+			Built:         true,
+			Booted:        true,
+			Memory:        2048,
+			TotalDiskSize: 40,
+			CPUS:          2,
+			Label:         fmt.Sprintf("web-%d", i),
+			Hostname:      fmt.Sprintf("web-%d.example.com", i),
+			TemplateLabel: "Ubuntu 24.04",
+			IPAddresses:   []domains.IPAddress{{Address: "192.0.2.10"}, {Address: "2001:db8::10"}},
+			Location:      domains.Location{Slug: "MIA1", Country: "United States", City: "Miami", Available: true},
+		})
+	}
+
+	b, _ := json.Marshal(resp)
+	return b
+}
+
+func snapshotsPayload(n int) []byte {
+	resp := domains.GetSnapshotsResponse{}
+	resp.Success = true
+	for i := 0; i < n; i++ {
+		resp.Data = append(resp.Data, domains.Snapshot{
+			Slug:        fmt.Sprintf("snap%06d", i),
+			Size:        20,
+			Label:       fmt.Sprintf("nightly %d", i),
+			OsReference: "ubuntu-24.04-x86_64",
+			Reference:   fmt.Sprintf("inst%06d", i),
+			Build:       true,
+			Locations:   []string{"MIA1", "SAO1"},
+		})
+	}
+
+	b, _ := json.Marshal(resp)
+	return b
+}
+
+func TestClient_TruncatedResponse(t *testing.T) {
+	body := instancesPayload(50)
+
+	tests := []struct {
+		name      string
+		truncated int32
+		wantErr   error
+	}{
+		{name: "retried", truncated: 1},
+		{name: "every attempt cut short", truncated: 2, wantErr: io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				if atomic.AddInt32(&hits, 1) <= tt.truncated {
+					w.Write(body[:len(body)/2])
+					return
+				}
+				w.Write(body)
+			}))
+			defer srv.Close()
+
+			c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithRetryPolicy(httpclient.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var meta ResponseMeta
+			got, err := c.Instances(WithResponseMeta(&meta))
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Instances() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrSendingRequest) {
+					t.Errorf("Instances() error = %v, want %v", err, ErrSendingRequest)
+				}
+				return
+			}
+
+			if len(got) != 50 || got[49].Identifier != "inst000049" || meta.Attempts != 2 {
+				t.Errorf("Instances() = %d instances after %d attempts, want 50 after 2", len(got), meta.Attempts)
+			}
+		})
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		out  func() interface{}
+	}{
+		{
+			name: "list",
+			body: `{"success": true, "message": "ok", "data": [{"identifier": "a", "ip_addresses": [{"address": "192.0.2.1"}]}, {"identifier": "b"}], "meta": {"total": 2}}`,
+			out:  func() interface{} { return &domains.GetInstancesResponse{} },
+		},
+		{
+			name: "empty list",
+			body: `{"success": true, "data": []}`,
+			out:  func() interface{} { return &domains.GetInstancesResponse{} },
+		},
+		{
+			name: "null list",
+			body: `{"success": true, "data": null}`,
+			out:  func() interface{} { return &domains.GetSnapshotsResponse{} },
+		},
+		{
+			name: "object and unknown fields",
+			body: `{"Success": true, "data": {"name": "John", "extra": [1, {"a": 2}]}, "links": {"next": null}}`,
+			out:  func() interface{} { return &domains.GetProfileResponse{} },
+		},
+		{
+			name: "raw data",
+			body: `{"success": true, "data": [{"id": 1}]}`,
+			out:  func() interface{} { return &rawResponse{Data: &[]map[string]int{}} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, want := tt.out(), tt.out()
+			if err := decodeStream(strings.NewReader(tt.body), got); err != nil {
+				t.Fatalf("decodeStream() error = %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.body), want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("decodeStream() = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	for _, body := range []string{`{"success": true, "data": [{"identifier": "a"}`, `{"data": {}}`, `[]`} {
+		if err := decodeStream(strings.NewReader(body), &domains.GetInstancesResponse{}); err == nil {
+			t.Errorf("decodeStream(%s) error = nil, want an error", body)
+		}
+	}
+}
+
+// BenchmarkClient_Decode lists 5000 instances and snapshots through the client,
+// with the response buffered before being decoded, as happens when middlewares
+// are registered, and decoded as it is read
+func BenchmarkClient_Decode(b *testing.B) {
+	payloads := []struct {
+		name string
+		body []byte
+		list func(c *LetsCloud) error
+	}{
+		{"Instances", instancesPayload(5000), func(c *LetsCloud) error { _, err := c.Instances(); return err }},
+		{"Snapshots", snapshotsPayload(5000), func(c *LetsCloud) error { _, err := c.Snapshots(); return err }},
+	}
+
+	for _, p := range payloads {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(p.body)
+		}))
+
+		modes := []struct {
+			name string
+			opts []Option
+		}{
+			{"buffered", []Option{WithMiddleware(func(next httpclient.SendFunc) httpclient.SendFunc { return next })}},
+			{"streamed", nil},
+		}
+		for _, m := range modes {
+			b.Run(p.name+"/"+m.name, func(b *testing.B) {
+				c, err := New(TEST_API_KEY, append(m.opts, WithBaseURL(srv.URL))...)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				b.ReportAllocs()
+				b.SetBytes(int64(len(p.body)))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if err := p.list(c); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+
+		srv.Close()
+	}
+}
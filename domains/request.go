@@ -2,42 +2,42 @@ package domains
 
 // SSHKeyCreateRequest is used for sending POST Request to create new SSH Key
 type SSHKeyCreateRequest struct {
-	Title string `json:"title"`
-	Key   string `json:"key,omitempty"`
+	Title string `json:"title" validate:"required,label"`
+	Key   string `json:"key,omitempty" validate:"omitempty,sshkey"`
 }
 
 // SSHKeyDelRequest is used for sending DELETE Request to delete an existing slug
 type SSHKeyDelRequest struct {
-	Slug string `json:"slug,omitempty"`
+	Slug string `json:"slug,omitempty" validate:"required,slug"`
 }
 
 // CreateInstanceRequest is used for sending POST Request to create a new instance
 type CreateInstanceRequest struct {
-	LocationSlug string `json:"location_slug"`
-	PlanSlug     string `json:"plan_slug"`
-	Hostname     string `json:"hostname"`
-	Label        string `json:"label"`
-	ImageSlug    string `json:"image_slug"`
-	SSHSlug      string `json:"ssh_slug,omitempty"`
-	Password     string `json:"password,omitempty" validate:"omitempty,min=8"`
+	LocationSlug string `json:"location_slug" validate:"required,slug"`
+	PlanSlug     string `json:"plan_slug" validate:"required,slug"`
+	Hostname     string `json:"hostname" validate:"required,hostname"`
+	Label        string `json:"label" validate:"required,label"`
+	ImageSlug    string `json:"image_slug" validate:"required,slug"`
+	SSHSlug      string `json:"ssh_slug,omitempty" validate:"omitempty,slug"`
+	Password     string `json:"password,omitempty" validate:"omitempty,min=8,password"`
 }
 
 // InstanceResetPasswordRequest is used for sending PUT Request to reset the password of an existing instance
 type InstanceResetPasswordRequest struct {
-	Password string `json:"password,omitempty"`
+	Password string `json:"password,omitempty" validate:"required,min=8,password"`
 }
 
 // SnapshotCreateRequest is used for sending POST Request to create a new snapshot
 type SnapshotCreateRequest struct {
-	Label string `json:"label"`
+	Label string `json:"label" validate:"required,label"`
 }
 
 // SnapshotDeleteRequest is used for sending DELETE Request to delete an existing snapshot
 type SnapshotDeleteRequest struct {
-	ImageSlug string `json:"image_slug,omitempty"`
+	ImageSlug string `json:"image_slug,omitempty" validate:"omitempty,slug"`
 }
 
 // SnapshotUpdateRequest is used for sending PUT Request to update an existing snapshot
 type SnapshotUpdateRequest struct {
-	Label string `json:"label"`
+	Label string `json:"label" validate:"required,label"`
 }
@@ -27,7 +27,8 @@ type GetLocationImagesResponse struct {
 // GetSSHKeysResponse represents the response data from the ssh keys GET request
 type GetSSHKeysResponse struct {
 	CommonResponse
-	Data []SSHKey `json:"data"`
+	Data []SSHKey  `json:"data"`
+	Meta *PageMeta `json:"meta,omitempty"`
 }
 
 // CreateOrGetSSHKeysResponse represents the response data from the locations GET/POST request
@@ -40,6 +41,7 @@ type CreateOrGetSSHKeysResponse struct {
 type GetInstancesResponse struct {
 	CommonResponse
 	Data []Instance `json:"data"`
+	Meta *PageMeta  `json:"meta,omitempty"`
 }
 
 // GetInstanceResponse represents the response data from the single instance GET request
@@ -54,6 +56,15 @@ type CommonResponse struct {
 	Message string `json:"message"`
 }
 
+// PageMeta describes the page of a list response
+type PageMeta struct {
+	CurrentPage int    `json:"current_page"`
+	PerPage     int    `json:"per_page"`
+	LastPage    int    `json:"last_page"`
+	Total       int    `json:"total"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+}
+
 // SSHKeyDelResponse represents the response data from the ssh key DELETE request
 type SSHKeyDelResponse struct {
 	Message string `json:"message,omitempty"`
@@ -69,4 +80,10 @@ type CreateOrGetSnapshotResponse struct {
 type GetSnapshotsResponse struct {
 	CommonResponse
 	Data []Snapshot `json:"data"`
+	Meta *PageMeta  `json:"meta,omitempty"`
+}
+
+// Common returns the fields shared by every response type
+func (r *CommonResponse) Common() *CommonResponse {
+	return r
 }
@@ -1,6 +1,13 @@
 package letscloud
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
 
 var (
 	ErrMakingRequest     = errors.New("error creating new request")
@@ -12,4 +19,95 @@ var (
 	ErrInvalidHttpClient = errors.New("error invalid http client provided")
 	ErrInvalidTimeout    = errors.New("error invalid timeout provided")
 	ErrCreatingInstance  = errors.New("error creating new instance")
+	ErrNotFound          = errors.New("error resource not found")
+	ErrUnauthorized      = errors.New("error unauthorized request")
+	ErrRateLimited       = errors.New("error rate limit exceeded")
+	ErrConflict          = errors.New("error conflicting request")
+	ErrServer            = errors.New("error on the server side")
+	ErrProfileNotFound   = errors.New("error profile not found")
+	ErrInvalidRequest    = errors.New("error invalid request")
+	ErrCircuitOpen       = httpclient.ErrCircuitOpen
 )
+
+// APIError is returned when the LetsCloud API rejects a request, either with a
+// non 2xx HTTP status or with a response whose success flag is false.
+//
+// It can be matched with errors.Is against ErrBadStatus, ErrNotFound,
+// ErrUnauthorized (401 and 403), ErrRateLimited, ErrConflict and ErrServer.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Body is the body of the response. It is empty when a 2xx response whose
+	// success flag is false was decoded as it was read, without being buffered.
+	Body   []byte
+	Header http.Header
+	// RequestID is the X-Request-ID sent with the request, when it reached the API
+	RequestID string
+}
+
+func newAPIError(status int, header http.Header, body []byte) *APIError {
+	e := &APIError{StatusCode: status, Body: body, Header: header}
+
+	var out struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &out) == nil {
+		e.Message = out.Message
+	}
+
+	if e.Message == "" {
+		e.Message = http.StatusText(status)
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("letscloud: %d %s (request %s)", e.StatusCode, e.Message, e.RequestID)
+	}
+
+	return fmt.Sprintf("letscloud: %d %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether the error belongs to the class described by target
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadStatus:
+		return e.StatusCode < 200 || e.StatusCode > 299
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+
+	return false
+}
+
+// wrappedError ties one of the sentinel errors above to the error that caused it
+type wrappedError struct {
+	sentinel error
+	err      error
+}
+
+func wrapError(sentinel, err error) error {
+	return &wrappedError{sentinel: sentinel, err: err}
+}
+
+func (e *wrappedError) Error() string {
+	return e.sentinel.Error() + ": " + e.err.Error()
+}
+
+func (e *wrappedError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.err
+}
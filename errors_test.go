@@ -0,0 +1,77 @@
+package letscloud
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/letscloud-community/letscloud-go/domains"
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		target error
+		want   bool
+	}{
+		{name: "not found", status: http.StatusNotFound, target: ErrNotFound, want: true},
+		{name: "unauthorized", status: http.StatusUnauthorized, target: ErrUnauthorized, want: true},
+		{name: "forbidden", status: http.StatusForbidden, target: ErrUnauthorized, want: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, target: ErrRateLimited, want: true},
+		{name: "conflict", status: http.StatusConflict, target: ErrConflict, want: true},
+		{name: "server", status: http.StatusBadGateway, target: ErrServer, want: true},
+		{name: "bad status", status: http.StatusBadRequest, target: ErrBadStatus, want: true},
+		{name: "success flag false", status: http.StatusOK, target: ErrBadStatus, want: false},
+		{name: "not found is not a server error", status: http.StatusNotFound, target: ErrServer, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := error(newAPIError(tt.status, nil, nil))
+			if got := errors.Is(err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_APIErrors(t *testing.T) {
+	mc := gomock.NewController(t)
+	defer mc.Finish()
+
+	mclient := httpclient.NewMockRequester(mc)
+
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil).Times(3)
+	mclient.EXPECT().SendRequest(gomock.Any()).Return(nil, &httpclient.StatusError{
+		StatusCode: http.StatusNotFound,
+		Body:       []byte(`{"success": false, "message": "Instance not found"}`),
+	})
+	mclient.EXPECT().SendRequest(gomock.Any()).Return([]byte(`{"success": false, "message": "Instance is locked"}`), nil)
+	mclient.EXPECT().SendRequest(gomock.Any()).Return(nil, &httpclient.StatusError{StatusCode: http.StatusServiceUnavailable})
+
+	c := &LetsCloud{requester: mclient}
+
+	_, err := c.Instance("identifier-example")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Instance not found" || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Instance() error = %v, want not found APIError", err)
+	}
+
+	err = c.RebootInstance("identifier-example")
+	if !errors.As(err, &apiErr) || apiErr.Message != "Instance is locked" {
+		t.Errorf("RebootInstance() error = %v, want APIError with server message", err)
+	}
+
+	err = c.CreateInstance(&domains.CreateInstanceRequest{
+		LocationSlug: "MIA1",
+		PlanSlug:     "1vcpu-1gb-10ssd",
+		Hostname:     "hostname-example",
+		Label:        "Label Example",
+		ImageSlug:    "ubuntu-20.04-x86_64",
+	})
+	if !errors.Is(err, ErrCreatingInstance) || !errors.Is(err, ErrServer) {
+		t.Errorf("CreateInstance() error = %v, want ErrCreatingInstance wrapping ErrServer", err)
+	}
+}
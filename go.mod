@@ -1,5 +1,19 @@
 module github.com/letscloud-community/letscloud-go
 
-go 1.14
+go 1.23.0
 
-require github.com/golang/mock v1.4.4
+require (
+	github.com/golang/mock v1.4.4
+	go.opentelemetry.io/otel v1.37.0
+	go.opentelemetry.io/otel/trace v1.37.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/metric v1.37.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.37.0
+	golang.org/x/sys v0.33.0 // indirect
+)
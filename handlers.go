@@ -1,97 +1,64 @@
 package letscloud
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/letscloud-community/letscloud-go/domains"
+	"github.com/letscloud-community/letscloud-go/httpclient"
 )
 
 // Profile retrieves the profile of current user
-func (c *LetsCloud) Profile() (*domains.Profile, error) {
-	c.debugLog("Fetching profile")
-
-	req, err := c.requester.NewRequest(http.MethodGet, "/profile", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	c.debugLog("Profile response: " + string(b))
+func (c *LetsCloud) Profile(opts ...RequestOption) (*domains.Profile, error) {
+	return c.ProfileContext(context.Background(), opts...)
+}
 
+// ProfileContext retrieves the profile of current user using the given context
+func (c *LetsCloud) ProfileContext(ctx context.Context, opts ...RequestOption) (*domains.Profile, error) {
 	var out domains.GetProfileResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "Profile", http.MethodGet, "/profile", nil, &out, opts); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
-	c.debugLog("Profile fetched successfully")
 	return &out.Data, nil
 }
 
 // Locations fetches all the locations of letscloud
-func (c *LetsCloud) Locations() ([]domains.Location, error) {
-	req, err := c.requester.NewRequest(http.MethodGet, "/locations", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
+func (c *LetsCloud) Locations(opts ...RequestOption) ([]domains.Location, error) {
+	return c.LocationsContext(context.Background(), opts...)
+}
 
+// LocationsContext fetches all the locations of letscloud using the given context
+func (c *LetsCloud) LocationsContext(ctx context.Context, opts ...RequestOption) ([]domains.Location, error) {
 	var out domains.GetLocationsResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "Locations", http.MethodGet, "/locations", nil, &out, opts); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return out.Data, nil
 }
 
 // LocationPlans fetches all the pricing plans of the given location
-func (c *LetsCloud) LocationPlans(slug string) ([]domains.Plan, error) {
+func (c *LetsCloud) LocationPlans(slug string, opts ...RequestOption) ([]domains.Plan, error) {
+	return c.LocationPlansContext(context.Background(), slug, opts...)
+}
+
+// LocationPlansContext fetches all the pricing plans of the given location using the given context
+func (c *LetsCloud) LocationPlansContext(ctx context.Context, slug string, opts ...RequestOption) ([]domains.Plan, error) {
 	if slug == "" {
 		return nil, errors.New("please provide a valid location slug")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodGet, fmt.Sprintf("/locations/%s/plans", slug), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
 	var out domains.GetLocationPlansResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "LocationPlans", http.MethodGet, fmt.Sprintf("/locations/%s/plans", slug), nil, &out, opts, locationAttr(slug)); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	var allPlans []domains.Plan
 	for _, location := range out.Data {
 		allPlans = append(allPlans, location.Plans...)
@@ -101,546 +68,396 @@ func (c *LetsCloud) LocationPlans(slug string) ([]domains.Plan, error) {
 }
 
 // LocationImages fetches all the VM images of the given location
-func (c *LetsCloud) LocationImages(slug string) ([]domains.Image, error) {
+func (c *LetsCloud) LocationImages(slug string, opts ...RequestOption) ([]domains.Image, error) {
+	return c.LocationImagesContext(context.Background(), slug, opts...)
+}
+
+// LocationImagesContext fetches all the VM images of the given location using the given context
+func (c *LetsCloud) LocationImagesContext(ctx context.Context, slug string, opts ...RequestOption) ([]domains.Image, error) {
 	if slug == "" {
 		return nil, errors.New("please provide a valid location slug")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodGet, fmt.Sprintf("/locations/%s/images", slug), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
 	var out domains.GetLocationImagesResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "LocationImages", http.MethodGet, fmt.Sprintf("/locations/%s/images", slug), nil, &out, opts, locationAttr(slug)); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return out.Data, nil
 }
 
 // NewSSHKey creates a new SSH key
-func (c *LetsCloud) NewSSHKey(title, key string) (*domains.SSHKey, error) {
-	payload := domains.SSHKeyCreateRequest{Title: title}
-
-	if err := validateStruct(payload); err != nil {
-		return nil, err
-	}
-
-	if key != "" {
-		payload.Key = key
-	}
+func (c *LetsCloud) NewSSHKey(title, key string, opts ...RequestOption) (*domains.SSHKey, error) {
+	return c.NewSSHKeyContext(context.Background(), title, key, opts...)
+}
 
-	req, err := c.requester.NewRequest(http.MethodPost, "/sshkeys", payload)
-	if err != nil {
-		return nil, err
-	}
+// NewSSHKeyContext creates a new SSH key using the given context
+func (c *LetsCloud) NewSSHKeyContext(ctx context.Context, title, key string, opts ...RequestOption) (*domains.SSHKey, error) {
+	payload := domains.SSHKeyCreateRequest{Title: title, Key: key}
 
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
+	if err := Validate(payload); err != nil {
 		return nil, err
 	}
 
 	var out domains.CreateOrGetSSHKeysResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "NewSSHKey", http.MethodPost, "/sshkeys", payload, &out, opts); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return &out.Data, nil
 }
 
-// SSHKeys returns all the SSH key of current user
-func (c *LetsCloud) SSHKeys() ([]domains.SSHKey, error) {
-	req, err := c.requester.NewRequest(http.MethodGet, "/sshkeys", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
+// SSHKeys returns all the SSH key of current user in a single request, see ListAllSSHKeys for large accounts
+func (c *LetsCloud) SSHKeys(opts ...RequestOption) ([]domains.SSHKey, error) {
+	return c.SSHKeysContext(context.Background(), opts...)
+}
 
+// SSHKeysContext returns all the SSH key of current user using the given context
+func (c *LetsCloud) SSHKeysContext(ctx context.Context, opts ...RequestOption) ([]domains.SSHKey, error) {
 	var out domains.GetSSHKeysResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "SSHKeys", http.MethodGet, "/sshkeys", nil, &out, opts); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return out.Data, nil
 }
 
 // SSHKey retrieves details of a given SSH key of current user
-func (c *LetsCloud) SSHKey(title string) (*domains.SSHKey, error) {
+func (c *LetsCloud) SSHKey(title string, opts ...RequestOption) (*domains.SSHKey, error) {
+	return c.SSHKeyContext(context.Background(), title, opts...)
+}
+
+// SSHKeyContext retrieves details of a given SSH key of current user using the given context
+func (c *LetsCloud) SSHKeyContext(ctx context.Context, title string, opts ...RequestOption) (*domains.SSHKey, error) {
 	if title == "" {
 		return nil, errors.New("please provide a valid ssh key title")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodGet, "/sshkeys/"+title, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
 	var out domains.CreateOrGetSSHKeysResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "SSHKey", http.MethodGet, "/sshkeys/"+title, nil, &out, opts, sshKeyAttr(title)); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return &out.Data, nil
 }
 
 // DeleteSSHKey deletes an existing SSH key of current user
-func (c *LetsCloud) DeleteSSHKey(slug string) error {
+func (c *LetsCloud) DeleteSSHKey(slug string, opts ...RequestOption) error {
+	return c.DeleteSSHKeyContext(context.Background(), slug, opts...)
+}
+
+// DeleteSSHKeyContext deletes an existing SSH key of current user using the given context
+func (c *LetsCloud) DeleteSSHKeyContext(ctx context.Context, slug string, opts ...RequestOption) error {
 	if slug == "" {
 		return errors.New("please provide a valid slug")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodDelete, "/sshkeys", domains.SSHKeyDelRequest{Slug: slug})
-	if err != nil {
-		return err
-	}
+	payload := domains.SSHKeyDelRequest{Slug: slug}
 
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
+	if err := Validate(payload); err != nil {
 		return err
 	}
 
 	var out domains.CreateOrGetSSHKeysResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "DeleteSSHKey", http.MethodDelete, "/sshkeys", payload, &out, opts, sshKeyAttr(slug)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
 
-// Instances fetches all the instances created by the current user
-func (c *LetsCloud) Instances() ([]domains.Instance, error) {
-	req, err := c.requester.NewRequest(http.MethodGet, "/instances", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
+// Instances fetches all the instances created by the current user in a single request,
+// see ListAllInstances for large accounts
+func (c *LetsCloud) Instances(opts ...RequestOption) ([]domains.Instance, error) {
+	return c.InstancesContext(context.Background(), opts...)
+}
 
+// InstancesContext fetches all the instances created by the current user using the given context
+func (c *LetsCloud) InstancesContext(ctx context.Context, opts ...RequestOption) ([]domains.Instance, error) {
 	var out domains.GetInstancesResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "Instances", http.MethodGet, "/instances", nil, &out, opts); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return out.Data, nil
 }
 
 // CreateInstance creates a new instance
-func (c *LetsCloud) CreateInstance(request *domains.CreateInstanceRequest) error {
-	if request == nil {
-		return errors.New("please provide valid data in order to create instance")
-	}
+func (c *LetsCloud) CreateInstance(request *domains.CreateInstanceRequest, opts ...RequestOption) error {
+	return c.CreateInstanceContext(context.Background(), request, opts...)
+}
 
-	if *request == (domains.CreateInstanceRequest{}) {
+// CreateInstanceContext creates a new instance using the given context.
+// The request carries an idempotency key reused by its retries. Before a retry,
+// the instances are searched for a new one with the hostname and label of
+// request, which the failed attempt may have created. When retries are enabled,
+// the instances matching them are thus listed before the first attempt, and a
+// failure to list them stops the retries.
+func (c *LetsCloud) CreateInstanceContext(ctx context.Context, request *domains.CreateInstanceRequest, opts ...RequestOption) error {
+	if request == nil {
 		return errors.New("please provide valid data in order to create instance")
 	}
 
-	if err := validateStruct(*request); err != nil {
-		return err
-	}
-
-	req, err := c.requester.NewRequest(http.MethodPost, "/instances", request)
-	if err != nil {
-		return err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
+	if err := Validate(request); err != nil {
 		return err
 	}
 
 	var out domains.GetInstanceResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
-		return err
-	}
+	ctx = httpclient.WithRetryGuard(ctx, c.createdInstance(request))
 
-	if !out.Success {
-		return errors.New(out.Message)
+	if err := c.do(ctx, "CreateInstance", http.MethodPost, "/instances", request, &out, idempotent(opts),
+		locationAttr(request.LocationSlug)); err != nil {
+		return wrapError(ErrCreatingInstance, err)
 	}
 
 	return nil
 }
 
 // Instance gets details about a particular instance of the current user
-func (c *LetsCloud) Instance(identifier string) (*domains.Instance, error) {
+func (c *LetsCloud) Instance(identifier string, opts ...RequestOption) (*domains.Instance, error) {
+	return c.InstanceContext(context.Background(), identifier, opts...)
+}
+
+// InstanceContext gets details about a particular instance of the current user using the given context
+func (c *LetsCloud) InstanceContext(ctx context.Context, identifier string, opts ...RequestOption) (*domains.Instance, error) {
 	if identifier == "" {
 		return nil, errors.New("please provide a valid instance identifier")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodGet, "/instances/"+identifier, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
 	var out domains.GetInstanceResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "Instance", http.MethodGet, "/instances/"+identifier, nil, &out, opts, instanceAttr(identifier)); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return &out.Data, nil
 }
 
 // DeleteInstance deletes any existing instance of the user
-func (c *LetsCloud) DeleteInstance(identifier string) error {
+func (c *LetsCloud) DeleteInstance(identifier string, opts ...RequestOption) error {
+	return c.DeleteInstanceContext(context.Background(), identifier, opts...)
+}
+
+// DeleteInstanceContext deletes any existing instance of the user using the given context
+func (c *LetsCloud) DeleteInstanceContext(ctx context.Context, identifier string, opts ...RequestOption) error {
 	if identifier == "" {
 		return errors.New("please provide a valid instance identifier")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodDelete, "/instances/"+identifier, nil)
-	if err != nil {
-		return err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return err
-	}
-
 	var out domains.CommonResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "DeleteInstance", http.MethodDelete, "/instances/"+identifier, nil, &out, opts, instanceAttr(identifier)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
 
 // PowerOnInstance turns on any existing instance of the current user
-func (c *LetsCloud) PowerOnInstance(identifier string) error {
+func (c *LetsCloud) PowerOnInstance(identifier string, opts ...RequestOption) error {
+	return c.PowerOnInstanceContext(context.Background(), identifier, opts...)
+}
+
+// PowerOnInstanceContext turns on any existing instance of the current user using the given context
+func (c *LetsCloud) PowerOnInstanceContext(ctx context.Context, identifier string, opts ...RequestOption) error {
 	if identifier == "" {
 		return errors.New("please provide a valid instance identifier")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodPut, "/instances/"+identifier+"/power-on", nil)
-	if err != nil {
-		return err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return err
-	}
-
 	var out domains.CommonResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "PowerOnInstance", http.MethodPut, "/instances/"+identifier+"/power-on", nil, &out, opts, instanceAttr(identifier)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
 
 // PowerOffInstance turns off any existing instance of the current user
-func (c *LetsCloud) PowerOffInstance(identifier string) error {
+func (c *LetsCloud) PowerOffInstance(identifier string, opts ...RequestOption) error {
+	return c.PowerOffInstanceContext(context.Background(), identifier, opts...)
+}
+
+// PowerOffInstanceContext turns off any existing instance of the current user using the given context
+func (c *LetsCloud) PowerOffInstanceContext(ctx context.Context, identifier string, opts ...RequestOption) error {
 	if identifier == "" {
 		return errors.New("please provide a valid instance identifier")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodPut, "/instances/"+identifier+"/power-off", nil)
-	if err != nil {
-		return err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return err
-	}
-
 	var out domains.CommonResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "PowerOffInstance", http.MethodPut, "/instances/"+identifier+"/power-off", nil, &out, opts, instanceAttr(identifier)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
 
 // RebootInstance as the name suggests, it reboots the instance
-func (c *LetsCloud) RebootInstance(identifier string) error {
+func (c *LetsCloud) RebootInstance(identifier string, opts ...RequestOption) error {
+	return c.RebootInstanceContext(context.Background(), identifier, opts...)
+}
+
+// RebootInstanceContext reboots the instance using the given context
+func (c *LetsCloud) RebootInstanceContext(ctx context.Context, identifier string, opts ...RequestOption) error {
 	if identifier == "" {
 		return errors.New("please provide a valid instance identifier")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodPut, "/instances/"+identifier+"/reboot", nil)
-	if err != nil {
-		return err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return err
-	}
-
 	var out domains.CommonResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "RebootInstance", http.MethodPut, "/instances/"+identifier+"/reboot", nil, &out, opts, instanceAttr(identifier)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
 
 // ResetPasswordInstance is used for resetting the forgotten password of any instance
-func (c *LetsCloud) ResetPasswordInstance(identifier, newPassword string) error {
+func (c *LetsCloud) ResetPasswordInstance(identifier, newPassword string, opts ...RequestOption) error {
+	return c.ResetPasswordInstanceContext(context.Background(), identifier, newPassword, opts...)
+}
+
+// ResetPasswordInstanceContext resets the password of any instance using the given context
+func (c *LetsCloud) ResetPasswordInstanceContext(ctx context.Context, identifier, newPassword string, opts ...RequestOption) error {
 	if identifier == "" || newPassword == "" {
 		return errors.New("please provide a valid instance identifier and new password")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodPut, "/instances/"+identifier+"/reset-password",
-		domains.InstanceResetPasswordRequest{Password: newPassword})
-	if err != nil {
-		return err
-	}
+	payload := domains.InstanceResetPasswordRequest{Password: newPassword}
 
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
+	if err := Validate(payload); err != nil {
 		return err
 	}
 
 	var out domains.CommonResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "ResetPasswordInstance", http.MethodPut, "/instances/"+identifier+"/reset-password",
+		payload, &out, opts, instanceAttr(identifier)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
 
 // NewSnapshot creates a new snapshot of the instance
-func (c *LetsCloud) NewSnapshot(label, identifier string) (*domains.CreateOrGetSnapshotResponse, error) {
+func (c *LetsCloud) NewSnapshot(label, identifier string, opts ...RequestOption) (*domains.CreateOrGetSnapshotResponse, error) {
+	return c.NewSnapshotContext(context.Background(), label, identifier, opts...)
+}
+
+// NewSnapshotContext creates a new snapshot of the instance using the given context.
+// Like CreateInstanceContext, retries reuse an idempotency key and look for a
+// new snapshot labelled label of the instance first.
+func (c *LetsCloud) NewSnapshotContext(ctx context.Context, label, identifier string, opts ...RequestOption) (*domains.CreateOrGetSnapshotResponse, error) {
 	if identifier == "" || label == "" {
 		return nil, errors.New("please provide a valid instance identifier and label")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodPost, "/instances/"+identifier+"/snapshots",
-		domains.SnapshotCreateRequest{Label: label})
-	if err != nil {
-		return nil, err
-	}
+	payload := domains.SnapshotCreateRequest{Label: label}
 
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
+	if err := Validate(payload); err != nil {
 		return nil, err
 	}
 
 	var out domains.CreateOrGetSnapshotResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
-		return nil, err
-	}
+	ctx = httpclient.WithRetryGuard(ctx, c.createdSnapshot(label, identifier))
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
+	if err := c.do(ctx, "NewSnapshot", http.MethodPost, "/instances/"+identifier+"/snapshots",
+		payload, &out, idempotent(opts), instanceAttr(identifier)); err != nil {
+		return nil, err
 	}
 
 	return &out, nil
 }
 
-// Snapshots fetches all the snapshots of the current user
-func (c *LetsCloud) Snapshots() ([]domains.Snapshot, error) {
-	req, err := c.requester.NewRequest(http.MethodGet, "/snapshots", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
+// Snapshots fetches all the snapshots of the current user in a single request,
+// see ListAllSnapshots for large accounts
+func (c *LetsCloud) Snapshots(opts ...RequestOption) ([]domains.Snapshot, error) {
+	return c.SnapshotsContext(context.Background(), opts...)
+}
 
+// SnapshotsContext fetches all the snapshots of the current user using the given context
+func (c *LetsCloud) SnapshotsContext(ctx context.Context, opts ...RequestOption) ([]domains.Snapshot, error) {
 	var out domains.GetSnapshotsResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "Snapshots", http.MethodGet, "/snapshots", nil, &out, opts); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return out.Data, nil
 }
 
 // Snapshot gets details about a particular snapshot of the current user
-func (c *LetsCloud) Snapshot(slug string) (*domains.Snapshot, error) {
+func (c *LetsCloud) Snapshot(slug string, opts ...RequestOption) (*domains.Snapshot, error) {
+	return c.SnapshotContext(context.Background(), slug, opts...)
+}
+
+// SnapshotContext gets details about a particular snapshot of the current user using the given context
+func (c *LetsCloud) SnapshotContext(ctx context.Context, slug string, opts ...RequestOption) (*domains.Snapshot, error) {
 	if slug == "" {
 		return nil, errors.New("please provide a valid snapshot slug")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodGet, "/snapshots/"+slug, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
 	var out domains.CreateOrGetSnapshotResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "Snapshot", http.MethodGet, "/snapshots/"+slug, nil, &out, opts, snapshotAttr(slug)); err != nil {
 		return nil, err
 	}
 
-	if !out.Success {
-		return nil, errors.New(out.Message)
-	}
-
 	return &out.Data, nil
 }
 
 // UpdateSnapshot updates an existing snapshot of the current user
-func (c *LetsCloud) UpdateSnapshot(slug, label string) error {
+func (c *LetsCloud) UpdateSnapshot(slug, label string, opts ...RequestOption) error {
+	return c.UpdateSnapshotContext(context.Background(), slug, label, opts...)
+}
+
+// UpdateSnapshotContext updates an existing snapshot of the current user using the given context
+func (c *LetsCloud) UpdateSnapshotContext(ctx context.Context, slug, label string, opts ...RequestOption) error {
 	if slug == "" || label == "" {
 		return errors.New("please provide a valid snapshot slug and label")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodPut, "/snapshots/"+slug,
-		domains.SnapshotUpdateRequest{Label: label})
-	if err != nil {
-		return err
-	}
+	payload := domains.SnapshotUpdateRequest{Label: label}
 
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
+	if err := Validate(payload); err != nil {
 		return err
 	}
 
 	var out domains.CommonResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "UpdateSnapshot", http.MethodPut, "/snapshots/"+slug,
+		payload, &out, opts, snapshotAttr(slug)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
 
 // DeleteSnapshot deletes an existing snapshot of the current user
-func (c *LetsCloud) DeleteSnapshot(slug string) error {
+func (c *LetsCloud) DeleteSnapshot(slug string, opts ...RequestOption) error {
+	return c.DeleteSnapshotContext(context.Background(), slug, opts...)
+}
+
+// DeleteSnapshotContext deletes an existing snapshot of the current user using the given context
+func (c *LetsCloud) DeleteSnapshotContext(ctx context.Context, slug string, opts ...RequestOption) error {
 	if slug == "" {
 		return errors.New("please provide a valid snapshot slug")
 	}
 
-	req, err := c.requester.NewRequest(http.MethodDelete, "/snapshots/"+slug, nil)
-	if err != nil {
-		return err
-	}
-
-	b, err := c.requester.SendRequest(req)
-	if err != nil {
-		return err
-	}
-
 	var out domains.CommonResponse
 
-	err = processResponse(b, &out)
-	if err != nil {
+	if err := c.do(ctx, "DeleteSnapshot", http.MethodDelete, "/snapshots/"+slug, nil, &out, opts, snapshotAttr(slug)); err != nil {
 		return err
 	}
 
-	if !out.Success {
-		return errors.New(out.Message)
-	}
-
 	return nil
 }
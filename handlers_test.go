@@ -1,9 +1,13 @@
 package letscloud
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -22,10 +26,10 @@ func TestClient_CreateInstance(t *testing.T) {
 
 	mclient := httpclient.NewMockRequester(mc)
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return([]byte(`{"success": true, "message": "Instance successfully created"}`), nil)
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(nil, errors.New("server not reachable"))
 
 	type fields struct {
@@ -88,7 +92,7 @@ func TestClient_CreateInstance(t *testing.T) {
 				request: &domains.CreateInstanceRequest{
 					LocationSlug: "MIA1",
 					PlanSlug:     "1vcpu-1gb-10ssd",
-					Hostname:     "Hostname Example",
+					Hostname:     "hostname-example",
 					Label:        "Label Example",
 					ImageSlug:    "ubuntu-20.04-x86_64",
 				},
@@ -106,7 +110,7 @@ func TestClient_CreateInstance(t *testing.T) {
 				request: &domains.CreateInstanceRequest{
 					LocationSlug: "MIA1",
 					PlanSlug:     "1vcpu-1gb-10ssd",
-					Hostname:     "Hostname Example",
+					Hostname:     "hostname-example",
 					Label:        "Label Example",
 					ImageSlug:    "ubuntu-20.04-x86_64",
 				},
@@ -133,7 +137,7 @@ func TestClient_DeleteInstance(t *testing.T) {
 
 	mclient := httpclient.NewMockRequester(mc)
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return([]byte(`{"success": true, "message": "Instance successfully deleted"}`), nil)
 
 	type fields struct {
@@ -191,7 +195,7 @@ func TestClient_DeleteSSHKey(t *testing.T) {
 
 	mclient := httpclient.NewMockRequester(mc)
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return([]byte(`{"success": true, "message": "SSH Key was successfully deleted!"}`), nil)
 
 	type fields struct {
@@ -250,7 +254,7 @@ func TestClient_Instance(t *testing.T) {
 
 	mclient := httpclient.NewMockRequester(mc)
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return([]byte(`{"success": true, "data": {"identifier": "identifier-example"}}`), nil)
 
 	type fields struct {
@@ -382,7 +386,7 @@ func TestClient_Instances(t *testing.T) {
 		Data: instances,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(b, nil)
 
 	type fields struct {
@@ -451,7 +455,7 @@ func TestClient_LocationImages(t *testing.T) {
 		Data: images,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(imagesResp, nil)
 
 	type fields struct {
@@ -540,12 +544,11 @@ func TestClient_LocationPlans(t *testing.T) {
 	plansResp, _ := json.Marshal(domains.GetLocationPlansResponse{
 		CommonResponse: domains.CommonResponse{
 			Success: true,
-			Status: 200,
 		},
-		Data: mockPlans,
+		Data: plans,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(plansResp, nil)
 
 	type fields struct {
@@ -633,7 +636,7 @@ func TestClient_Locations(t *testing.T) {
 		Data: locations,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(locationsResp, nil)
 
 	type fields struct {
@@ -710,7 +713,7 @@ func TestClient_NewSSHKey(t *testing.T) {
 		Data: newKeyV2,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil).Times(2)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil).Times(2)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(sshCreateResp, nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(sshCreateRespV2, nil)
 
@@ -762,7 +765,7 @@ func TestClient_NewSSHKey(t *testing.T) {
 			},
 			args: args{
 				title: "my-ssh",
-				key:   "<this-is-a-test-key>",
+				key:   testSSHKey,
 			},
 			want:    &newKeyV2,
 			wantErr: false,
@@ -797,7 +800,7 @@ func TestClient_PowerOffInstance(t *testing.T) {
 		Message: "your instance has been turned off",
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(powerOffResp, nil)
 
 	type fields struct {
@@ -858,7 +861,7 @@ func TestClient_PowerOnInstance(t *testing.T) {
 		Message: "your instance has been turned on",
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(powerOnResp, nil)
 
 	type fields struct {
@@ -929,7 +932,7 @@ func TestClient_Profile(t *testing.T) {
 		Data: profile,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(getProfileResp, nil)
 
 	type fields struct {
@@ -983,7 +986,7 @@ func TestClient_RebootInstance(t *testing.T) {
 		Message: "your instance has been rebooted",
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(rebootResp, nil)
 
 	type fields struct {
@@ -1044,7 +1047,7 @@ func TestClient_ResetPasswordInstance(t *testing.T) {
 		Message: "your password reset successful",
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(resetPassResp, nil)
 
 	type fields struct {
@@ -1080,7 +1083,7 @@ func TestClient_ResetPasswordInstance(t *testing.T) {
 			},
 			args: args{
 				identifier:  "identifier-example",
-				newPassword: "<new-password>",
+				newPassword: "N3w-password!",
 			},
 			wantErr: false,
 		},
@@ -1119,7 +1122,7 @@ func TestClient_SSHKey(t *testing.T) {
 		Data: sshKey,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(getSSHResp, nil)
 
 	type fields struct {
@@ -1199,7 +1202,7 @@ func TestClient_SSHKeys(t *testing.T) {
 		Data: sshKeys,
 	})
 
-	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
+	mclient.EXPECT().NewRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(new(http.Request), nil)
 	mclient.EXPECT().SendRequest(gomock.Any()).Return(getSSHResp, nil)
 
 	type fields struct {
@@ -1241,3 +1244,113 @@ func TestClient_SSHKeys(t *testing.T) {
 		})
 	}
 }
+
+// ctxValueMatcher matches a context carrying the given value for key
+type ctxValueMatcher struct {
+	key, value interface{}
+}
+
+func (m ctxValueMatcher) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	return ok && ctx.Value(m.key) == m.value
+}
+
+func (m ctxValueMatcher) String() string {
+	return fmt.Sprintf("context with %v=%v", m.key, m.value)
+}
+
+func TestClient_InstancesContext(t *testing.T) {
+	mc := gomock.NewController(t)
+	defer mc.Finish()
+
+	mclient := httpclient.NewMockRequester(mc)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	mclient.EXPECT().NewRequest(ctxValueMatcher{key: ctxKey{}, value: "value"}, http.MethodGet, "/instances", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, method, url string, data interface{}) (*http.Request, error) {
+			if op := httpclient.Operation(ctx); op != "Instances" {
+				t.Errorf("request operation = %q, want Instances", op)
+			}
+			return new(http.Request), nil
+		})
+	mclient.EXPECT().SendRequest(gomock.Any()).Return([]byte(`{"success": true, "data": []}`), nil)
+
+	c := &LetsCloud{requester: mclient}
+
+	if _, err := c.InstancesContext(ctx); err != nil {
+		t.Errorf("InstancesContext() error = %v, wantErr false", err)
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/firewalls":
+			body, _ := io.ReadAll(r.Body)
+			w.Write([]byte(`{"success": true, "message": "ok", "data": [{"name": "web", "request": ` + string(body) + `}]}`))
+		case "/locked":
+			w.Write([]byte(`{"success": false, "message": "Resource is locked"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success": false, "message": "Not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	type firewall struct {
+		Name    string            `json:"name"`
+		Request map[string]string `json:"request"`
+	}
+
+	var data []firewall
+	if err := c.Do(context.Background(), http.MethodPost, "/firewalls", map[string]string{"port": "22"}, &data); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if want := []firewall{{Name: "web", Request: map[string]string{"port": "22"}}}; !reflect.DeepEqual(data, want) {
+		t.Errorf("Do() data = %+v, want %+v", data, want)
+	}
+
+	var envelope struct {
+		domains.CommonResponse
+		Data []firewall `json:"data"`
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/firewalls", nil, &envelope); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if envelope.Message != "ok" || len(envelope.Data) != 1 {
+		t.Errorf("Do() envelope = %+v", envelope)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "success flag false", path: "/locked"},
+		{name: "not found", path: "/unknown", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Do(context.Background(), http.MethodGet, tt.path, nil, nil)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Do() error = %v, want an APIError", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := c.Do(context.Background(), http.MethodGet, "firewalls", nil, nil); err == nil {
+		t.Errorf("Do() with a relative path returned no error")
+	}
+}
@@ -0,0 +1,260 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through while counting failures
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every request with ErrCircuitOpen until the cool-down is over
+	BreakerOpen
+	// BreakerHalfOpen lets a few trial requests through to decide whether to close or open again
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// BreakerConfig configures a CircuitBreaker. Zero values use the defaults.
+type BreakerConfig struct {
+	// FailureRatio is the share of failed requests, from 0 to 1, opening the circuit. It defaults to 0.5.
+	FailureRatio float64
+	// MinRequests is the number of requests within Window needed before the
+	// circuit can open, so a few failures do not open it. It defaults to 10.
+	MinRequests int
+	// Window is the period over which requests are counted while closed. It defaults to 1 minute.
+	Window time.Duration
+	// CoolDown is how long the circuit stays open before trial requests are let through. It defaults to 30s.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of trial requests that must succeed to close the circuit. It defaults to 1.
+	HalfOpenRequests int
+	// IsFailure reports whether the outcome of a request counts as a failure,
+	// any other outcome counting as a success. Nil counts transport errors and
+	// 5xx statuses. Canceled contexts and errors met before the request was sent,
+	// such as ErrRateLimitWait, tell nothing about the API and are ignored
+	// without calling IsFailure.
+	IsFailure func(resp *Response, err error) bool
+	// OnStateChange is called whenever the circuit changes state, e.g. for alerting.
+	// It is called while the breaker is locked, so it must return quickly and
+	// must not use the breaker.
+	OnStateChange func(from, to BreakerState)
+}
+
+// CircuitBreaker stops sending requests for a while once too many of them fail,
+// so callers fail fast while the API is down instead of waiting for timeouts.
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	cfg BreakerConfig
+
+	mu          sync.Mutex
+	state       BreakerState
+	generation  uint64
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	trials      int
+	successes   int
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureRatio <= 0 || cfg.FailureRatio > 1 {
+		cfg.FailureRatio = 0.5
+	}
+	if cfg.MinRequests < 1 {
+		cfg.MinRequests = 10
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = 30 * time.Second
+	}
+	if cfg.HalfOpenRequests < 1 {
+		cfg.HalfOpenRequests = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isFailure
+	}
+
+	return &CircuitBreaker{cfg: cfg, windowStart: time.Now()}
+}
+
+// State returns the current state of the circuit
+func (cb *CircuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	from := cb.state
+	cb.expire(time.Now())
+	defer func() { cb.notify(from, cb.state) }()
+
+	return cb.state
+}
+
+// allow reports whether a request may be sent, along with the generation of
+// the circuit its outcome is recorded in
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	from := cb.state
+	cb.expire(time.Now())
+	defer func() { cb.notify(from, cb.state) }()
+
+	switch cb.state {
+	case BreakerOpen:
+		return 0, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if cb.trials >= cb.cfg.HalfOpenRequests {
+			return 0, ErrCircuitOpen
+		}
+		cb.trials++
+	}
+
+	return cb.generation, nil
+}
+
+// outcome is the classification of the outcome of a request
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeIgnored
+)
+
+// classify tells whether the outcome of a request is a success, a failure or
+// must be ignored
+func (cb *CircuitBreaker) classify(resp *Response, err error) outcome {
+	switch {
+	case ignored(err):
+		return outcomeIgnored
+	case cb.cfg.IsFailure(resp, err):
+		return outcomeFailure
+	}
+
+	return outcomeSuccess
+}
+
+// record counts the outcome of a request let through by allow. Outcomes of
+// requests sent before the last change of state are ignored, as are ignored
+// outcomes, which give their trial back in half-open.
+func (cb *CircuitBreaker) record(generation uint64, resp *Response, err error) {
+	result := cb.classify(resp, err)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := time.Now()
+	from := cb.state
+	cb.expire(now)
+	defer func() { cb.notify(from, cb.state) }()
+
+	if generation != cb.generation {
+		return
+	}
+
+	if result == outcomeIgnored {
+		if cb.state == BreakerHalfOpen {
+			cb.trials--
+		}
+		return
+	}
+
+	failed := result == outcomeFailure
+
+	switch cb.state {
+	case BreakerClosed:
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if cb.requests >= cb.cfg.MinRequests && float64(cb.failures) >= cb.cfg.FailureRatio*float64(cb.requests) {
+			cb.setState(BreakerOpen, now)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			cb.setState(BreakerOpen, now)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.cfg.HalfOpenRequests {
+			cb.setState(BreakerClosed, now)
+		}
+	}
+}
+
+// expire moves an open circuit to half-open once the cool-down is over, and
+// starts a new window for a closed circuit once the current one is over
+func (cb *CircuitBreaker) expire(now time.Time) {
+	switch cb.state {
+	case BreakerOpen:
+		if now.Sub(cb.openedAt) >= cb.cfg.CoolDown {
+			cb.setState(BreakerHalfOpen, now)
+		}
+	case BreakerClosed:
+		if now.Sub(cb.windowStart) >= cb.cfg.Window {
+			cb.windowStart = now
+			cb.requests, cb.failures = 0, 0
+		}
+	}
+}
+
+func (cb *CircuitBreaker) setState(state BreakerState, now time.Time) {
+	cb.state = state
+	cb.generation++
+	cb.windowStart = now
+	cb.requests, cb.failures = 0, 0
+	cb.trials, cb.successes = 0, 0
+
+	if state == BreakerOpen {
+		cb.openedAt = now
+	}
+}
+
+// notify calls OnStateChange when the state changed, with the lock held so
+// the changes are reported in order
+func (cb *CircuitBreaker) notify(from, to BreakerState) {
+	if from != to && cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(from, to)
+	}
+}
+
+// isFailure counts transport errors and 5xx statuses as failures
+func isFailure(resp *Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp != nil && resp.StatusCode >= http.StatusInternalServerError
+}
+
+// ignored reports whether err tells nothing about the API: canceled contexts
+// and errors met before the request was sent, such as ErrRateLimitWait
+func ignored(err error) bool {
+	var unsent unsentError
+	return err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, ErrRateLimitWait) || errors.As(err, &unsent))
+}
@@ -0,0 +1,310 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var failing atomic.Bool
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	var changes []string
+	cb := NewCircuitBreaker(BreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		CoolDown:     20 * time.Millisecond,
+		OnStateChange: func(from, to BreakerState) {
+			changes = append(changes, from.String()+" -> "+to.String())
+		},
+	})
+
+	h := NewHttpClient("token")
+	h.SetBaseURL(srv.URL)
+	h.SetCircuitBreaker(cb)
+
+	send := func() error {
+		req, err := h.NewRequest(context.Background(), http.MethodGet, "/profile", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		_, err = h.SendRequest(req)
+		return err
+	}
+
+	steps := []struct {
+		name      string
+		failing   bool
+		wait      time.Duration
+		wantErr   error
+		wantSent  bool
+		wantState BreakerState
+	}{
+		{name: "success", wantSent: true, wantState: BreakerClosed},
+		{name: "success", wantSent: true, wantState: BreakerClosed},
+		{name: "failure", failing: true, wantSent: true, wantState: BreakerClosed},
+		{name: "failure reaching the ratio", failing: true, wantSent: true, wantState: BreakerOpen},
+		{name: "fails fast while open", wantErr: ErrCircuitOpen, wantState: BreakerOpen},
+		{name: "failed trial", failing: true, wait: 30 * time.Millisecond, wantSent: true, wantState: BreakerOpen},
+		{name: "fails fast again", wantErr: ErrCircuitOpen, wantState: BreakerOpen},
+		{name: "successful trial", wait: 30 * time.Millisecond, wantSent: true, wantState: BreakerClosed},
+	}
+	for i, step := range steps {
+		time.Sleep(step.wait)
+		failing.Store(step.failing)
+		before := atomic.LoadInt32(&hits)
+
+		err := send()
+
+		if step.wantErr != nil && !errors.Is(err, step.wantErr) {
+			t.Errorf("step %d (%s): SendRequest() error = %v, want %v", i, step.name, err, step.wantErr)
+		}
+		if sent := atomic.LoadInt32(&hits) > before; sent != step.wantSent {
+			t.Errorf("step %d (%s): request sent = %v, want %v", i, step.name, sent, step.wantSent)
+		}
+		if state := cb.State(); state != step.wantState {
+			t.Errorf("step %d (%s): State() = %v, want %v", i, step.name, state, step.wantState)
+		}
+	}
+
+	want := []string{"closed -> open", "open -> half-open", "half-open -> open", "open -> half-open", "half-open -> closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("state changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("state changes = %v, want %v", changes, want)
+			break
+		}
+	}
+}
+
+func TestCircuitBreaker_HalfOpenTrials(t *testing.T) {
+	cb := NewCircuitBreaker(BreakerConfig{MinRequests: 1, CoolDown: time.Millisecond, HalfOpenRequests: 2})
+
+	gen, _ := cb.allow()
+	cb.record(gen, nil, errors.New("connection reset"))
+	if cb.State() != BreakerOpen {
+		t.Fatalf("State() = %v after a failure, want open", cb.State())
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	first, err1 := cb.allow()
+	second, err2 := cb.allow()
+	if _, err := cb.allow(); err1 != nil || err2 != nil || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() in half-open = %v, %v, %v, want two trials", err1, err2, err)
+	}
+
+	cb.record(first, &Response{StatusCode: http.StatusOK}, nil)
+	if cb.State() != BreakerHalfOpen {
+		t.Errorf("State() = %v after one successful trial, want half-open", cb.State())
+	}
+
+	cb.record(second, &Response{StatusCode: http.StatusOK}, nil)
+	if cb.State() != BreakerClosed {
+		t.Errorf("State() = %v after two successful trials, want closed", cb.State())
+	}
+
+	// outcomes of requests sent before the circuit closed are ignored
+	cb.record(first, nil, errors.New("connection reset"))
+	if cb.State() != BreakerClosed {
+		t.Errorf("State() = %v after a stale failure, want closed", cb.State())
+	}
+
+	gen, _ = cb.allow()
+	cb.record(gen, nil, context.Canceled)
+	if cb.State() != BreakerClosed {
+		t.Errorf("State() = %v after a canceled request, want closed", cb.State())
+	}
+}
+
+func TestCircuitBreaker_RateLimitWait(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreaker(BreakerConfig{MinRequests: 1})
+
+	h := NewHttpClient("token")
+	h.SetBaseURL(srv.URL)
+	h.SetRateLimiter(NewRateLimiter(1, 1))
+	h.SetCircuitBreaker(cb)
+
+	// the first request takes the only token, the others give up waiting for one
+	for i := 0; i < 4; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		req, err := h.NewRequest(ctx, http.MethodGet, "/profile", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+
+		_, err = h.SendRequest(req)
+		cancel()
+
+		if i > 0 && !errors.Is(err, ErrRateLimitWait) {
+			t.Errorf("request %d: SendRequest() error = %v, want %v", i, err, ErrRateLimitWait)
+		}
+	}
+
+	if state := cb.State(); state != BreakerClosed || atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("State() = %v after %d requests sent, want closed after 1", state, hits)
+	}
+}
+
+func TestCircuitBreaker_IgnoredOutcomes(t *testing.T) {
+	outcomes := []struct {
+		name string
+		err  error
+	}{
+		{name: "canceled", err: context.Canceled},
+		{name: "rate limiter wait", err: ErrRateLimitWait},
+		{name: "not sent", err: unsentError{context.DeadlineExceeded}},
+	}
+	for _, o := range outcomes {
+		t.Run(o.name+" trial leaves the circuit half-open", func(t *testing.T) {
+			cb := NewCircuitBreaker(BreakerConfig{MinRequests: 1, CoolDown: time.Millisecond})
+
+			gen, _ := cb.allow()
+			cb.record(gen, nil, errors.New("connection reset"))
+			time.Sleep(5 * time.Millisecond)
+
+			gen, err := cb.allow()
+			if err != nil {
+				t.Fatalf("allow() in half-open error = %v", err)
+			}
+			cb.record(gen, nil, o.err)
+			if state := cb.State(); state != BreakerHalfOpen {
+				t.Fatalf("State() = %v after an ignored trial, want half-open", state)
+			}
+
+			// the trial is given back
+			if _, err := cb.allow(); err != nil {
+				t.Errorf("allow() after an ignored trial error = %v, want a new trial", err)
+			}
+		})
+
+		t.Run(o.name+" does not dilute failures", func(t *testing.T) {
+			cb := NewCircuitBreaker(BreakerConfig{FailureRatio: 0.5, MinRequests: 4})
+
+			for i := 0; i < 100; i++ {
+				gen, _ := cb.allow()
+				cb.record(gen, nil, o.err)
+			}
+			for i := 0; i < 4; i++ {
+				gen, _ := cb.allow()
+				cb.record(gen, &Response{StatusCode: http.StatusBadGateway}, nil)
+			}
+
+			if state := cb.State(); state != BreakerOpen {
+				t.Errorf("State() = %v after 4 failures following 100 ignored outcomes, want open", state)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_Classify(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *Response
+		err  error
+		want outcome
+	}{
+		{name: "success", resp: &Response{StatusCode: http.StatusOK}, want: outcomeSuccess},
+		{name: "client error", resp: &Response{StatusCode: http.StatusNotFound}, want: outcomeSuccess},
+		{name: "server error", resp: &Response{StatusCode: http.StatusBadGateway}, want: outcomeFailure},
+		{name: "transport error", err: errors.New("connection reset"), want: outcomeFailure},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: outcomeFailure},
+		{name: "canceled", err: context.Canceled, want: outcomeIgnored},
+		{name: "rate limiter wait", err: ErrRateLimitWait, want: outcomeIgnored},
+		{name: "not sent", err: unsentError{context.DeadlineExceeded}, want: outcomeIgnored},
+	}
+
+	cb := NewCircuitBreaker(BreakerConfig{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cb.classify(tt.resp, tt.err); got != tt.want {
+				t.Errorf("classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_RetryGuard(t *testing.T) {
+	var posts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && atomic.AddInt32(&posts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreaker(BreakerConfig{MinRequests: 1, CoolDown: time.Millisecond, HalfOpenRequests: 1})
+	gen, _ := cb.allow()
+	cb.record(gen, nil, errors.New("connection reset"))
+	time.Sleep(5 * time.Millisecond)
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	h := NewHttpClient("token")
+	h.SetBaseURL(srv.URL)
+	h.SetRetryPolicy(policy)
+	h.SetCircuitBreaker(cb)
+
+	// the requests of the guard are sent while the only trial is in flight
+	list := func(ctx context.Context) error {
+		req, err := h.NewRequest(ctx, http.MethodGet, "/instances", nil)
+		if err != nil {
+			return err
+		}
+		_, err = h.SendRequest(req)
+		return err
+	}
+
+	var guardErrs []error
+	ctx := WithRetryGuard(context.Background(), func(ctx context.Context) RetryGuard {
+		guardErrs = append(guardErrs, list(ctx))
+		return func(ctx context.Context) (*Response, error) {
+			err := list(ctx)
+			guardErrs = append(guardErrs, err)
+			return nil, err
+		}
+	})
+
+	req, err := h.NewRequest(ctx, http.MethodPost, "/instances", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set(IdempotencyKeyHeader, "key")
+
+	if _, err := h.SendRequest(req); err != nil {
+		t.Fatalf("SendRequest() error = %v", err)
+	}
+
+	if len(guardErrs) != 2 || guardErrs[0] != nil || guardErrs[1] != nil {
+		t.Errorf("guard requests errors = %v, want 2 requests sent", guardErrs)
+	}
+	if state := cb.State(); state != BreakerClosed || atomic.LoadInt32(&posts) != 2 {
+		t.Errorf("State() = %v after %d posts, want closed after 2", state, posts)
+	}
+}
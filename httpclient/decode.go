@@ -0,0 +1,40 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+)
+
+type streamDecoderKey struct{}
+
+// WithStreamDecoder returns a copy of ctx whose successful responses are passed
+// to fn as they are read, instead of being buffered, leaving Response.Body nil.
+// fn is called once per attempt. An error met reading the body fails the
+// attempt as a transport error, so it is retried like one, and the outcome fn
+// decoded for that attempt must be discarded. Bodies are still buffered for
+// non 2xx responses, when middlewares are registered, so they always see the
+// body, and when debug logging is on.
+func WithStreamDecoder(ctx context.Context, fn func(body io.Reader)) context.Context {
+	return context.WithValue(ctx, streamDecoderKey{}, fn)
+}
+
+func streamDecoder(ctx context.Context) func(io.Reader) {
+	fn, _ := ctx.Value(streamDecoderKey{}).(func(io.Reader))
+	return fn
+}
+
+// bodyReader records the first error met reading a response body, other than
+// io.EOF, so a broken connection is told apart from a malformed body
+type bodyReader struct {
+	io.Reader
+	err error
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+
+	return n, err
+}
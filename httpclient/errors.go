@@ -0,0 +1,32 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// StatusError is returned by SendRequest when the API answers with a non 2xx status
+type StatusError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	if e.StatusCode == http.StatusUnauthorized {
+		return "401 unauthorized: please check your api key"
+	}
+
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// unsentError wraps an error met before the first attempt of a request was
+// sent, such as a rate limiter wait, so the circuit breaker can tell it apart
+// from a failure of the API
+type unsentError struct {
+	error
+}
+
+func (e unsentError) Unwrap() error {
+	return e.error
+}
@@ -0,0 +1,20 @@
+package httpclient
+
+import "context"
+
+type responseHooksKey struct{}
+
+// WithResponseHook returns a copy of ctx whose requests call fn with the
+// final response, once retries and middlewares are done
+func WithResponseHook(ctx context.Context, fn func(*Response)) context.Context {
+	hooks, _ := ctx.Value(responseHooksKey{}).([]func(*Response))
+	hooks = append(hooks[:len(hooks):len(hooks)], fn)
+	return context.WithValue(ctx, responseHooksKey{}, hooks)
+}
+
+func runResponseHooks(ctx context.Context, resp *Response) {
+	hooks, _ := ctx.Value(responseHooksKey{}).([]func(*Response))
+	for _, fn := range hooks {
+		fn(resp)
+	}
+}
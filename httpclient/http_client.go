@@ -2,10 +2,13 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"io/ioutil"
+	"io"
+	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -14,35 +17,122 @@ const (
 	defaultBaseURL = "https://core.letscloud.io/api"
 )
 
+// httpClient is safe for concurrent use: its settings may change while requests are in flight
 type httpClient struct {
-	apiKey  string
-	baseURL string
-	httpcl  *http.Client
+	mu        sync.RWMutex
+	cfg       settings
+	refreshMu sync.Mutex
+}
+
+// settings holds the configuration of a client. A copy is taken at the start of
+// every request, so setters never affect requests already in flight.
+type settings struct {
+	apiKey      string
+	baseURL     string
+	httpcl      *http.Client
+	retryPolicy RetryPolicy
+	limiter     *RateLimiter
+	breaker     *CircuitBreaker
+	middlewares []Middleware
+	logger      *slog.Logger
+	refresher   KeyRefresher
+}
+
+// KeyRefresher returns a new API key after a request was rejected with 401 Unauthorized
+type KeyRefresher func(ctx context.Context) (string, error)
+
+func (h *httpClient) settings() settings {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.cfg
+}
+
+func (h *httpClient) update(fn func(s *settings)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fn(&h.cfg)
 }
 
 func (h *httpClient) APIKey() string {
-	return h.apiKey
+	return h.settings().apiKey
 }
 
 func (h *httpClient) SetAPIKey(t string) {
-	h.apiKey = t
+	h.update(func(s *settings) { s.apiKey = t })
 }
 
 func (h *httpClient) SetBaseURL(url string) {
-	h.baseURL = url
+	h.update(func(s *settings) { s.baseURL = url })
 }
 
+// SetTimeout sets the timeout on a copy of the underlying http.Client,
+// so clients supplied through SetHTTPClient are never modified
 func (h *httpClient) SetTimeout(d time.Duration) {
-	h.httpcl.Timeout = d
+	h.update(func(s *settings) {
+		cl := *s.httpcl
+		cl.Timeout = d
+		s.httpcl = &cl
+	})
+}
+
+// SetLogger logs every attempt with the given logger. A nil logger disables logging.
+func (h *httpClient) SetLogger(l *slog.Logger) {
+	h.update(func(s *settings) { s.logger = l })
 }
 
-func (h *httpClient) NewRequest(method, endpoint string, data interface{}) (*http.Request, error) {
-	if h.apiKey == "" {
+// Use appends middlewares to the chain wrapping every request, the first one being the outermost
+func (h *httpClient) Use(mw ...Middleware) {
+	h.update(func(s *settings) {
+		s.middlewares = append(s.middlewares[:len(s.middlewares):len(s.middlewares)], mw...)
+	})
+}
+
+// SetHTTPClient replaces the underlying http.Client
+func (h *httpClient) SetHTTPClient(cl *http.Client) {
+	h.update(func(s *settings) { s.httpcl = cl })
+}
+
+// SetTransport replaces the transport of the underlying http.Client
+func (h *httpClient) SetTransport(rt http.RoundTripper) {
+	h.update(func(s *settings) {
+		cl := *s.httpcl
+		cl.Transport = rt
+		s.httpcl = &cl
+	})
+}
+
+func (h *httpClient) SetRetryPolicy(p RetryPolicy) {
+	h.update(func(s *settings) { s.retryPolicy = p })
+}
+
+func (h *httpClient) SetRateLimiter(l *RateLimiter) {
+	h.update(func(s *settings) { s.limiter = l })
+}
+
+// SetCircuitBreaker fails requests with ErrCircuitOpen, without sending them,
+// while cb is open. A nil cb removes the circuit breaker.
+func (h *httpClient) SetCircuitBreaker(cb *CircuitBreaker) {
+	h.update(func(s *settings) { s.breaker = cb })
+}
+
+// SetKeyRefresher sets the callback asked for a new API key when a request is
+// rejected with 401 Unauthorized. The request is then sent once more with the new key.
+func (h *httpClient) SetKeyRefresher(fn KeyRefresher) {
+	h.update(func(s *settings) { s.refresher = fn })
+}
+
+func (h *httpClient) NewRequest(ctx context.Context, method, endpoint string, data interface{}) (*http.Request, error) {
+	s := h.settings()
+
+	if s.apiKey == "" {
 		return nil, errors.New("no api key found. provide your api-key")
 	}
 
-	if h.baseURL == "" {
-		h.baseURL = defaultBaseURL
+	baseURL := s.baseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
 	}
 
 	b, err := json.Marshal(data)
@@ -50,12 +140,12 @@ func (h *httpClient) NewRequest(method, endpoint string, data interface{}) (*htt
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, h.baseURL+endpoint, bytes.NewReader(b))
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+endpoint, bytes.NewReader(b))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("api-token", h.apiKey)
+	req.Header.Add("api-token", s.apiKey)
 
 	if data != nil {
 		req.Header.Add("Content-Type", "application/json")
@@ -65,32 +155,297 @@ func (h *httpClient) NewRequest(method, endpoint string, data interface{}) (*htt
 }
 
 func (h *httpClient) SendRequest(req *http.Request) ([]byte, error) {
-	resp, err := h.httpcl.Do(req)
+	s := h.settings()
+
+	// requests of retry guards run within the request they guard
+	if fromGuard(req.Context()) {
+		s.breaker = nil
+	}
+
+	var generation uint64
+	if s.breaker != nil {
+		var err error
+		if generation, err = s.breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.chain()(req)
+
+	if err == nil && resp != nil && resp.StatusCode == http.StatusUnauthorized && s.refresher != nil && h.refreshAPIKey(req, s) {
+		if err = rewindBody(req); err == nil {
+			resp, err = h.settings().chain()(req)
+		}
+	}
+
+	if s.breaker != nil {
+		s.breaker.record(generation, resp, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp == nil {
+		return nil, errors.New("no response returned by middleware")
+	}
+
+	runResponseHooks(req.Context(), resp)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, Body: resp.Body}
+	}
+
+	return resp.Body, nil
+}
+
+// refreshAPIKey asks the key refresher for a new key after req was rejected and sets it
+// on req. Requests failing concurrently share a single refresh: when the key changed
+// since req was built, the current key is used as is.
+func (h *httpClient) refreshAPIKey(req *http.Request, s settings) bool {
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+
+	key := h.APIKey()
+
+	if key == req.Header.Get("api-token") {
+		var err error
+		if key, err = s.refresher(req.Context()); err != nil || key == "" {
+			if s.logger != nil {
+				s.logger.LogAttrs(req.Context(), slog.LevelWarn, "letscloud api key refresh failed",
+					slog.Any("error", err))
+			}
+			return false
+		}
+		h.SetAPIKey(key)
+	}
+
+	req.Header.Set("api-token", key)
+
+	return true
+}
+
+// chain wraps sendWithRetry with the middlewares
+func (s settings) chain() SendFunc {
+	send := s.sendWithRetry
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		send = s.middlewares[i](send)
+	}
+
+	return send
+}
+
+// rewindBody resets the body of req so it can be sent again
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+
+	return nil
+}
+
+// sendWithRetry sends req, retrying it according to the retry policy
+func (s settings) sendWithRetry(req *http.Request) (*Response, error) {
+	policy := s.retryPolicy
+
+	attempts := 1
+	if policy.enabled() && policy.allows(req) {
+		attempts = policy.MaxAttempts
+	}
+
+	var guard RetryGuard
+	if prepare := retryGuard(req.Context()); prepare != nil && attempts > 1 {
+		guard = prepare(detach(req.Context()))
+	}
+
+	var resp *Response
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			if guard != nil {
+				done, guardErr := guard(detach(req.Context()))
+				if guardErr != nil {
+					if s.logger != nil {
+						s.logger.LogAttrs(req.Context(), slog.LevelWarn, "letscloud retry guard failed",
+							slog.String("method", req.Method),
+							slog.String("endpoint", req.URL.Path),
+							slog.String("error", guardErr.Error()))
+					}
+					return resp, err
+				}
+				if done != nil {
+					done.Attempts = attempt - 1
+					return done, nil
+				}
+			}
+
+			if err := rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err = s.send(req, attempt)
+		if resp != nil {
+			resp.Attempts = attempt
+		}
+
+		if attempt >= attempts {
+			return resp, err
+		}
+
+		var delay time.Duration
+
+		switch {
+		case err != nil:
+			if !policy.retryError(err) {
+				return nil, err
+			}
+			delay = policy.backoff(attempt)
+		case policy.retryStatus(resp.StatusCode):
+			delay = policy.backoff(attempt)
+			if ra, ok := retryAfter(resp.Header); ok {
+				if policy.MaxDelay > 0 && ra > policy.MaxDelay {
+					return resp, nil
+				}
+				if ra > delay {
+					delay = ra
+				}
+			}
+		default:
+			return resp, nil
+		}
+
+		if s.logger != nil {
+			s.logger.LogAttrs(req.Context(), slog.LevelInfo, "letscloud retrying request",
+				slog.String("method", req.Method),
+				slog.String("endpoint", req.URL.Path),
+				slog.Int("attempt", attempt),
+				slog.Duration("delay", delay))
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send performs a single attempt of req, waiting for the rate limiter first
+func (s settings) send(req *http.Request, attempt int) (*Response, error) {
+	if s.limiter != nil {
+		if err := s.limiter.Wait(req.Context()); err != nil {
+			if attempt == 1 {
+				return nil, unsentError{err}
+			}
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	resp, err := s.roundTrip(req)
+	s.logAttempt(req, attempt, resp, err, time.Since(start))
+
+	return resp, err
+}
+
+// roundTrip sends req once and reads the whole response body
+func (s settings) roundTrip(req *http.Request) (*Response, error) {
+	resp, err := s.httpcl.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, errors.New("401 unauthorized: please check your api key")
+	if s.limiter != nil {
+		s.limiter.Observe(resp)
+	}
+
+	ctx := req.Context()
+	if decode := streamDecoder(ctx); decode != nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 &&
+		len(s.middlewares) == 0 && (s.logger == nil || !s.logger.Enabled(ctx, slog.LevelDebug)) {
+		body := &bodyReader{Reader: resp.Body}
+		decode(body)
+		if body.err != nil {
+			return nil, body.err
+		}
+		// drain what the decoder left so the connection can be reused
+		io.Copy(io.Discard, resp.Body)
+		return &Response{StatusCode: resp.StatusCode, Header: resp.Header}, nil
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return ioutil.ReadAll(resp.Body)
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	return ioutil.ReadAll(resp.Body)
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: b}, nil
+}
+
+// logAttempt logs the outcome of a single attempt. Headers and bodies are only
+// logged at debug level and always go through RedactHeader and RedactBody.
+func (s settings) logAttempt(req *http.Request, attempt int, resp *Response, err error, latency time.Duration) {
+	if s.logger == nil {
+		return
+	}
+
+	ctx := req.Context()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.URL.Path),
+		slog.Int("attempt", attempt),
+		slog.Duration("latency", latency),
+	}
+
+	if id := req.Header.Get("X-Request-ID"); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "letscloud request failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+
+	if s.logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs,
+			slog.Any("request_header", RedactHeader(req.Header)),
+			slog.String("response_body", RedactBody(resp.Body)))
+	}
+
+	// 304 Not Modified answers the revalidation of a cached response
+	level := slog.LevelDebug
+	if (resp.StatusCode < 200 || resp.StatusCode > 299) && resp.StatusCode != http.StatusNotModified {
+		level = slog.LevelWarn
+	}
+
+	s.logger.LogAttrs(ctx, level, "letscloud request", attrs...)
 }
 
 func (h *httpClient) Do(req *http.Request) (*http.Response, error) {
-	return h.httpcl.Do(req)
+	return h.settings().httpcl.Do(req)
+}
+
+// Clone returns a client with a copy of the settings of h. Both share the
+// underlying http.Client, hence its connection pool, and the rate limiter.
+func (h *httpClient) Clone() Requester {
+	return &httpClient{cfg: h.settings()}
 }
 
 // NewHttpClient creates a new instance of httpClient
 func NewHttpClient(apiKey string) *httpClient {
 	return &httpClient{
-		apiKey:  apiKey,
-		baseURL: defaultBaseURL,
-		httpcl:  &http.Client{Timeout: defaultTimeout},
+		cfg: settings{
+			apiKey:  apiKey,
+			baseURL: defaultBaseURL,
+			httpcl:  &http.Client{Timeout: defaultTimeout},
+		},
 	}
 }
@@ -1,12 +1,14 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: letscloud.go
+// Source: requester.go
 //+build !test
 
 // Package httpclient is a generated GoMock package.
 package httpclient
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
+	slog "log/slog"
 	http "net/http"
 	reflect "reflect"
 	time "time"
@@ -36,18 +38,18 @@ func (m *MockRequester) EXPECT() *MockRequesterMockRecorder {
 }
 
 // NewRequest mocks base method
-func (m *MockRequester) NewRequest(method, url string, data interface{}) (*http.Request, error) {
+func (m *MockRequester) NewRequest(ctx context.Context, method, url string, data interface{}) (*http.Request, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewRequest", method, url, data)
+	ret := m.ctrl.Call(m, "NewRequest", ctx, method, url, data)
 	ret0, _ := ret[0].(*http.Request)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // NewRequest indicates an expected call of NewRequest
-func (mr *MockRequesterMockRecorder) NewRequest(method, url, data interface{}) *gomock.Call {
+func (mr *MockRequesterMockRecorder) NewRequest(ctx, method, url, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewRequest", reflect.TypeOf((*MockRequester)(nil).NewRequest), method, url, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewRequest", reflect.TypeOf((*MockRequester)(nil).NewRequest), ctx, method, url, data)
 }
 
 // SendRequest mocks base method
@@ -89,6 +91,120 @@ func (mr *MockRequesterMockRecorder) SetBaseURL(url interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBaseURL", reflect.TypeOf((*MockRequester)(nil).SetBaseURL), url)
 }
 
+// SetRetryPolicy mocks base method
+func (m *MockRequester) SetRetryPolicy(p RetryPolicy) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRetryPolicy", p)
+}
+
+// SetRetryPolicy indicates an expected call of SetRetryPolicy
+func (mr *MockRequesterMockRecorder) SetRetryPolicy(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRetryPolicy", reflect.TypeOf((*MockRequester)(nil).SetRetryPolicy), p)
+}
+
+// SetRateLimiter mocks base method
+func (m *MockRequester) SetRateLimiter(l *RateLimiter) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRateLimiter", l)
+}
+
+// SetRateLimiter indicates an expected call of SetRateLimiter
+func (mr *MockRequesterMockRecorder) SetRateLimiter(l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRateLimiter", reflect.TypeOf((*MockRequester)(nil).SetRateLimiter), l)
+}
+
+// SetCircuitBreaker mocks base method
+func (m *MockRequester) SetCircuitBreaker(cb *CircuitBreaker) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCircuitBreaker", cb)
+}
+
+// SetCircuitBreaker indicates an expected call of SetCircuitBreaker
+func (mr *MockRequesterMockRecorder) SetCircuitBreaker(cb interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCircuitBreaker", reflect.TypeOf((*MockRequester)(nil).SetCircuitBreaker), cb)
+}
+
+// SetHTTPClient mocks base method
+func (m *MockRequester) SetHTTPClient(cl *http.Client) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetHTTPClient", cl)
+}
+
+// SetHTTPClient indicates an expected call of SetHTTPClient
+func (mr *MockRequesterMockRecorder) SetHTTPClient(cl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHTTPClient", reflect.TypeOf((*MockRequester)(nil).SetHTTPClient), cl)
+}
+
+// SetTransport mocks base method
+func (m *MockRequester) SetTransport(rt http.RoundTripper) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTransport", rt)
+}
+
+// SetTransport indicates an expected call of SetTransport
+func (mr *MockRequesterMockRecorder) SetTransport(rt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransport", reflect.TypeOf((*MockRequester)(nil).SetTransport), rt)
+}
+
+// Use mocks base method
+func (m *MockRequester) Use(mw ...Middleware) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range mw {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Use", varargs...)
+}
+
+// Use indicates an expected call of Use
+func (mr *MockRequesterMockRecorder) Use(mw ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockRequester)(nil).Use), mw...)
+}
+
+// SetLogger mocks base method
+func (m *MockRequester) SetLogger(l *slog.Logger) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLogger", l)
+}
+
+// SetLogger indicates an expected call of SetLogger
+func (mr *MockRequesterMockRecorder) SetLogger(l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogger", reflect.TypeOf((*MockRequester)(nil).SetLogger), l)
+}
+
+// SetKeyRefresher mocks base method
+func (m *MockRequester) SetKeyRefresher(fn KeyRefresher) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetKeyRefresher", fn)
+}
+
+// SetKeyRefresher indicates an expected call of SetKeyRefresher
+func (mr *MockRequesterMockRecorder) SetKeyRefresher(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyRefresher", reflect.TypeOf((*MockRequester)(nil).SetKeyRefresher), fn)
+}
+
+// Clone mocks base method
+func (m *MockRequester) Clone() Requester {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clone")
+	ret0, _ := ret[0].(Requester)
+	return ret0
+}
+
+// Clone indicates an expected call of Clone
+func (mr *MockRequesterMockRecorder) Clone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clone", reflect.TypeOf((*MockRequester)(nil).Clone))
+}
+
 // SetAPIKey mocks base method
 func (m *MockRequester) SetAPIKey(t string) {
 	m.ctrl.T.Helper()
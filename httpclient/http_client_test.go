@@ -0,0 +1,305 @@
+package httpclient
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_SendRequestCanceledContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	h := NewHttpClient("token")
+	h.SetBaseURL(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := h.NewRequest(ctx, http.MethodGet, "/profile", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if _, err := h.SendRequest(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("SendRequest() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestHttpClient_KeyRefresher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-token") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		refresher  KeyRefresher
+		wantCalls  int32
+		wantStatus int
+		wantKey    string
+	}{
+		{
+			name:      "refreshed key",
+			refresher: func(context.Context) (string, error) { return "new-token", nil },
+			wantCalls: 1,
+			wantKey:   "new-token",
+		},
+		{
+			name:       "refresher error",
+			refresher:  func(context.Context) (string, error) { return "", errors.New("vault sealed") },
+			wantCalls:  1,
+			wantStatus: http.StatusUnauthorized,
+			wantKey:    "old-token",
+		},
+		{
+			name:       "still unauthorized",
+			refresher:  func(context.Context) (string, error) { return "other-token", nil },
+			wantCalls:  1,
+			wantStatus: http.StatusUnauthorized,
+			wantKey:    "other-token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			h := NewHttpClient("old-token")
+			h.SetBaseURL(srv.URL)
+			h.SetKeyRefresher(func(ctx context.Context) (string, error) {
+				atomic.AddInt32(&calls, 1)
+				return tt.refresher(ctx)
+			})
+
+			req, err := h.NewRequest(context.Background(), http.MethodPost, "/snapshots", map[string]string{"label": "backup"})
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+
+			body, err := h.SendRequest(req)
+
+			var statusErr *StatusError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Fatalf("SendRequest() error = %v", err)
+			case tt.wantStatus == 0 && string(body) != `{"label":"backup"}`:
+				t.Errorf("SendRequest() body = %s, want the request body sent again", body)
+			case tt.wantStatus != 0 && (!errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus):
+				t.Errorf("SendRequest() error = %v, want status %d", err, tt.wantStatus)
+			}
+
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("refresher called %d times, want %d", got, tt.wantCalls)
+			}
+			if got := h.APIKey(); got != tt.wantKey {
+				t.Errorf("APIKey() = %v, want %v", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestHttpClient_KeyRefresherConcurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-token") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	var calls int32
+
+	h := NewHttpClient("old-token")
+	h.SetBaseURL(srv.URL)
+	h.SetKeyRefresher(func(context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "new-token", nil
+	})
+
+	// build every request before sending any, so they all fail with the old key
+	reqs := make([]*http.Request, 10)
+	for i := range reqs {
+		req, err := h.NewRequest(context.Background(), http.MethodGet, "/profile", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		reqs[i] = req
+	}
+
+	var wg sync.WaitGroup
+	for _, req := range reqs {
+		wg.Add(1)
+		go func(req *http.Request) {
+			defer wg.Done()
+			if _, err := h.SendRequest(req); err != nil {
+				t.Errorf("SendRequest() error = %v", err)
+			}
+		}(req)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("refresher called %d times, want 1", got)
+	}
+}
+
+func TestHttpClient_ConcurrentSetAPIKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	h := NewHttpClient("token-0")
+	h.SetBaseURL(srv.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			h.SetAPIKey(fmt.Sprintf("token-%d", i))
+			h.SetTimeout(time.Second)
+			h.Use(HeaderMiddleware("X-Test", "1"))
+		}(i)
+		go func() {
+			defer wg.Done()
+			req, err := h.NewRequest(context.Background(), http.MethodGet, "/profile", nil)
+			if err != nil {
+				t.Errorf("NewRequest() error = %v", err)
+				return
+			}
+			if _, err := h.SendRequest(req); err != nil {
+				t.Errorf("SendRequest() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHttpClient_StreamDecoder(t *testing.T) {
+	const want = `{"success": true, "data": {"name": "John"}}`
+
+	var truncated atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/truncated" && !truncated.Swap(true) {
+			// the connection is closed once the announced body is cut short
+			w.Header().Set("Content-Length", "100")
+			w.Write([]byte(want[:20]))
+			return
+		}
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write([]byte(want))
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(want))
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		stream       bool
+		middleware   bool
+		wantStreamed bool
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "buffered", path: "/profile", wantAttempts: 1},
+		{name: "streamed", path: "/profile", stream: true, wantStreamed: true, wantAttempts: 1},
+		{name: "error statuses are buffered", path: "/missing", stream: true, wantAttempts: 1, wantErr: true},
+		{name: "middlewares see the body", path: "/profile", stream: true, middleware: true, wantAttempts: 1},
+		{name: "truncated body is retried", path: "/truncated", stream: true, wantStreamed: true, wantAttempts: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHttpClient("token")
+			h.SetBaseURL(srv.URL)
+			h.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+			var streamed string
+			ctx := context.Background()
+			if tt.stream {
+				ctx = WithStreamDecoder(ctx, func(body io.Reader) {
+					b, _ := io.ReadAll(body)
+					streamed = string(b)
+				})
+			}
+
+			var resp *Response
+			ctx = WithResponseHook(ctx, func(r *Response) { resp = r })
+
+			var seen []byte
+			if tt.middleware {
+				h.Use(func(next SendFunc) SendFunc {
+					return func(req *http.Request) (*Response, error) {
+						r, err := next(req)
+						if r != nil {
+							seen = r.Body
+						}
+						return r, err
+					}
+				})
+			}
+
+			req, err := h.NewRequest(ctx, http.MethodGet, tt.path, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+
+			body, err := h.SendRequest(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantStreamed {
+				if streamed != want || body != nil {
+					t.Errorf("streamed %q and returned %q, want the body streamed only", streamed, body)
+				}
+			} else {
+				var se *StatusError
+				if errors.As(err, &se) {
+					body = se.Body
+				}
+				if string(body) != want || streamed != "" {
+					t.Errorf("returned %q and streamed %q, want the body buffered only", body, streamed)
+				}
+			}
+
+			if tt.middleware && string(seen) != want {
+				t.Errorf("middleware saw %q, want the body", seen)
+			}
+			if resp.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", resp.Attempts, tt.wantAttempts)
+			}
+			if resp.Header.Get("Content-Encoding") != "" {
+				t.Errorf("Content-Encoding = %q after decompression", resp.Header.Get("Content-Encoding"))
+			}
+		})
+	}
+}
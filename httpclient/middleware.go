@@ -0,0 +1,30 @@
+package httpclient
+
+import "net/http"
+
+// Response holds the answer of the API to a request, as seen by middlewares
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Attempts is the number of attempts made, retries included
+	Attempts int
+}
+
+// SendFunc sends a request to the API, including any retries.
+// A non 2xx status is reported through Response, not as an error.
+type SendFunc func(req *http.Request) (*Response, error)
+
+// Middleware wraps the send step of a client. It may mutate the request
+// before calling next, and inspect or replace the response and error after it.
+type Middleware func(next SendFunc) SendFunc
+
+// HeaderMiddleware sets the given header on every request
+func HeaderMiddleware(key, value string) Middleware {
+	return func(next SendFunc) SendFunc {
+		return func(req *http.Request) (*Response, error) {
+			req.Header.Set(key, value)
+			return next(req)
+		}
+	}
+}
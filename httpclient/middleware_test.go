@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHttpClient_Use(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Team") != "infra" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success": false}`))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	var seen *Response
+
+	record := func(name string) Middleware {
+		return func(next SendFunc) SendFunc {
+			return func(req *http.Request) (*Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(req)
+				calls = append(calls, name+" after")
+				if name == "inner" {
+					seen = resp
+				}
+				return resp, err
+			}
+		}
+	}
+
+	h := NewHttpClient("token")
+	h.SetBaseURL(srv.URL)
+	h.Use(record("outer"), HeaderMiddleware("X-Team", "infra"))
+	h.Use(record("inner"))
+
+	req, err := h.NewRequest(context.Background(), http.MethodGet, "/instances/1", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if _, err := h.SendRequest(req); err == nil {
+		t.Errorf("SendRequest() error = nil, want status error")
+	}
+
+	wantCalls := []string{"outer before", "inner before", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("middleware calls = %v, want %v", calls, wantCalls)
+	}
+
+	if seen == nil || seen.StatusCode != http.StatusNotFound || string(seen.Body) != `{"success": false}` {
+		t.Errorf("middleware saw response %+v, want 404 with body", seen)
+	}
+}
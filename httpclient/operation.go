@@ -0,0 +1,16 @@
+package httpclient
+
+import "context"
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx carrying the name of the SDK operation, e.g. "CreateInstance"
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// Operation returns the name of the SDK operation carried by ctx, or "" if there is none
+func Operation(ctx context.Context) string {
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
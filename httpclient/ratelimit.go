@@ -0,0 +1,129 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitWait is returned when waiting for the rate limiter would exceed the context deadline
+var ErrRateLimitWait = errors.New("rate limit wait exceeds context deadline")
+
+// RateLimiter is a token bucket limiting the requests sent by a client.
+// It is safe for concurrent use and adapts to the X-RateLimit-Remaining,
+// X-RateLimit-Reset and Retry-After headers returned by the API.
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rps requests per second with bursts of up to burst requests
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+
+	var wait time.Duration
+	if l.tokens < 0 && l.rate > 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+
+	if pause := l.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && wait > 0 && deadline.Before(now.Add(wait)) {
+		l.tokens++
+		l.mu.Unlock()
+		return ErrRateLimitWait
+	}
+
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Observe adjusts the limiter to the rate limit state reported by the API
+func (l *RateLimiter) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		l.tokens = math.Min(l.tokens, float64(remaining))
+
+		if reset, ok := RateLimitReset(resp.Header, now); remaining <= 0 && ok && reset.After(l.pausedUntil) {
+			l.pausedUntil = reset
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if d, ok := retryAfter(resp.Header); ok && now.Add(d).After(l.pausedUntil) {
+			l.pausedUntil = now.Add(d)
+		}
+	}
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+		l.last = now
+	}
+}
+
+// RateLimitReset parses X-RateLimit-Reset, given either as a unix timestamp or in seconds from now
+func RateLimitReset(h http.Header, now time.Time) (time.Time, bool) {
+	v, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil || v < 0 {
+		return time.Time{}, false
+	}
+
+	if v > 1e9 {
+		return time.Unix(v, 0), true
+	}
+
+	return now.Add(time.Duration(v) * time.Second), true
+}
@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	// the burst covers two requests, the other two wait 10ms each
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Wait() took %v, want at least 15ms", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitDeadline(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err != ErrRateLimitWait {
+		t.Errorf("Wait() error = %v, want %v", err, ErrRateLimitWait)
+	}
+}
+
+func TestRateLimiter_Observe(t *testing.T) {
+	l := NewRateLimiter(1000, 10)
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", strconv.Itoa(1))
+	l.Observe(resp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err != ErrRateLimitWait {
+		t.Errorf("Wait() error = %v, want %v", err, ErrRateLimitWait)
+	}
+}
@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// secretHeaders lists the request and response headers never written to logs
+var secretHeaders = []string{"api-token", "Authorization", "Cookie", "Set-Cookie"}
+
+// secretFields lists the JSON fields whose values are never written to logs
+var secretFields = map[string]bool{
+	"initial_root_password": true,
+	"private_key":           true,
+	"password":              true,
+	"api_key":               true,
+	"api_token":             true,
+	"token":                 true,
+}
+
+// RedactHeader returns a copy of h with credentials replaced
+func RedactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, k := range secretHeaders {
+		if out.Get(k) != "" {
+			out.Set(k, redacted)
+		}
+	}
+
+	return out
+}
+
+// RedactBody returns a copy of a JSON body with the values of secret fields
+// such as initial_root_password and private_key replaced. Bodies that are not
+// JSON are summarized by their size.
+func RedactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if secretFields[strings.ToLower(k)] {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactValue(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = redactValue(val)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,111 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "instance root password",
+			body: `{"success":true,"data":{"identifier":"abc","initial_root_password":"s3cret"}}`,
+			want: `{"data":{"identifier":"abc","initial_root_password":"[REDACTED]"},"success":true}`,
+		},
+		{
+			name: "ssh private keys in a list",
+			body: `{"data":[{"slug":"key","private_key":"-----BEGIN"}]}`,
+			want: `{"data":[{"private_key":"[REDACTED]","slug":"key"}]}`,
+		},
+		{
+			name: "not json",
+			body: `<html>`,
+			want: `[6 bytes]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("RedactBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpClient_SetLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"data":{"initial_root_password":"s3cret"}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	h := NewHttpClient("topsecrettoken")
+	h.SetBaseURL(srv.URL)
+	h.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	req, err := h.NewRequest(context.Background(), http.MethodGet, "/instances/abc", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if _, err := h.SendRequest(req); err != nil {
+		t.Fatalf("SendRequest() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"topsecrettoken", "s3cret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output contains secret %q: %s", secret, out)
+		}
+	}
+	for _, want := range []string{"method=GET", "endpoint=/instances/abc", "status=200", "attempt=1", "latency="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output does not contain %q: %s", want, out)
+		}
+	}
+}
+
+func TestHttpClient_LogLevel(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{status: http.StatusOK, want: "level=DEBUG"},
+		{status: http.StatusNotModified, want: "level=DEBUG"},
+		{status: http.StatusNotFound, want: "level=WARN"},
+		{status: http.StatusServiceUnavailable, want: "level=WARN"},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			var buf bytes.Buffer
+			h := NewHttpClient("token")
+			h.SetBaseURL(srv.URL)
+			h.SetRetryPolicy(RetryPolicy{})
+			h.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+			req, err := h.NewRequest(context.Background(), http.MethodGet, "/instances/abc", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			h.SendRequest(req)
+
+			if out := buf.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, tt.want) {
+				t.Errorf("log output = %q, want a single line at %s", out, tt.want)
+			}
+		})
+	}
+}
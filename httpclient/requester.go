@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Requester defines the API that will be used for sending HTTP Requests to the letscloud API
+type Requester interface {
+	NewRequest(ctx context.Context, method, url string, data interface{}) (*http.Request, error)
+	SendRequest(req *http.Request) ([]byte, error)
+	SetTimeout(d time.Duration)
+	SetAPIKey(t string)
+	SetBaseURL(url string)
+	SetRetryPolicy(p RetryPolicy)
+	SetRateLimiter(l *RateLimiter)
+	SetCircuitBreaker(cb *CircuitBreaker)
+	SetHTTPClient(cl *http.Client)
+	SetTransport(rt http.RoundTripper)
+	Use(mw ...Middleware)
+	SetLogger(l *slog.Logger)
+	SetKeyRefresher(fn KeyRefresher)
+	APIKey() string
+	Clone() Requester
+}
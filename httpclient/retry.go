@@ -0,0 +1,199 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header identifying a request across its retries,
+// so the API can tell a retry from a new request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy defines when and how often a failed request is sent again
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// A value lower than 2 disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled on every following retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay. A Retry-After longer than MaxDelay stops retrying.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of each delay that is randomized
+	Jitter float64
+	// StatusCodes lists the HTTP status codes that are retried
+	StatusCodes []int
+	// RetryError reports whether a transport error is retried. Nil uses IsTemporaryError.
+	RetryError func(err error) bool
+	// RetryNonIdempotent allows retrying methods such as POST that may create resources twice.
+	// Requests carrying an Idempotency-Key header are retried regardless.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a policy suitable for most callers:
+// 4 attempts with an exponential backoff between 500ms and 30s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+		StatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// IsTemporaryError reports whether err is a network error worth retrying,
+// such as a timeout, a connection reset or a connection closed by the server
+func IsTemporaryError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+func (p RetryPolicy) enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// allows reports whether req may be sent more than once
+func (p RetryPolicy) allows(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return p.RetryNonIdempotent || req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+func (p RetryPolicy) retryStatus(code int) bool {
+	for _, c := range p.StatusCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p RetryPolicy) retryError(err error) bool {
+	if p.RetryError != nil {
+		return p.RetryError(err)
+	}
+
+	return IsTemporaryError(err)
+}
+
+type retryGuardKey struct{}
+
+// RetryGuard is called before a request is sent again. It returns a response
+// when a previous attempt took effect despite failing, e.g. when the resource
+// it creates exists, or nil to send the request again.
+type RetryGuard func(ctx context.Context) (*Response, error)
+
+// WithRetryGuard returns a copy of ctx whose requests call prepare before their
+// first attempt, when they may be retried, e.g. to record the resources existing
+// beforehand, and the guard it returns before every retry. The response
+// returned by the guard, if any, ends the request as if it had been sent, and
+// an error stops the retries. Both are called with a context carrying none of
+// the hooks of the request, so they can send requests themselves. Those
+// requests bypass the circuit breaker, as they are part of a request it
+// already let through.
+func WithRetryGuard(ctx context.Context, prepare func(ctx context.Context) RetryGuard) context.Context {
+	return context.WithValue(ctx, retryGuardKey{}, prepare)
+}
+
+// fromGuard reports whether ctx was given to a retry guard
+func fromGuard(ctx context.Context) bool {
+	guarded, _ := ctx.Value(guardedKey{}).(bool)
+	return guarded
+}
+
+func retryGuard(ctx context.Context) func(context.Context) RetryGuard {
+	fn, _ := ctx.Value(retryGuardKey{}).(func(context.Context) RetryGuard)
+	return fn
+}
+
+type guardedKey struct{}
+
+// detach returns a copy of ctx without the retry guard, response hooks and
+// stream decoder of the request it was made for, marked as made by a guard
+func detach(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, guardedKey{}, true)
+	ctx = context.WithValue(ctx, retryGuardKey{}, (func(context.Context) RetryGuard)(nil))
+	ctx = context.WithValue(ctx, responseHooksKey{}, ([]func(*Response))(nil))
+	return context.WithValue(ctx, streamDecoderKey{}, (func(io.Reader))(nil))
+}
+
+// backoff returns the delay before the given retry, starting at 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		j := math.Min(p.Jitter, 1)
+		d -= d * j * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as an HTTP date
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,234 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_SendRequestRetries(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+
+	postPolicy := policy
+	postPolicy.RetryNonIdempotent = true
+
+	tests := []struct {
+		name           string
+		policy         RetryPolicy
+		method         string
+		idempotencyKey string
+		failures       int32
+		retryAfter     string
+		wantAttempts   int32
+		wantErr        bool
+	}{
+		{
+			name:         "no policy sends once",
+			method:       http.MethodGet,
+			failures:     1,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "retries get until success",
+			policy:       policy,
+			method:       http.MethodGet,
+			failures:     2,
+			wantAttempts: 3,
+		},
+		{
+			name:         "stops after max attempts",
+			policy:       policy,
+			method:       http.MethodGet,
+			failures:     10,
+			wantAttempts: 4,
+			wantErr:      true,
+		},
+		{
+			name:         "does not retry post by default",
+			policy:       policy,
+			method:       http.MethodPost,
+			failures:     1,
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "retries post when opted in",
+			policy:       postPolicy,
+			method:       http.MethodPost,
+			failures:     1,
+			wantAttempts: 2,
+		},
+		{
+			name:           "retries post with an idempotency key",
+			policy:         policy,
+			method:         http.MethodPost,
+			idempotencyKey: "key-1",
+			failures:       1,
+			wantAttempts:   2,
+		},
+		{
+			name:         "gives up when retry-after exceeds max delay",
+			policy:       policy,
+			method:       http.MethodGet,
+			failures:     1,
+			retryAfter:   "120",
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get(IdempotencyKeyHeader); got != tt.idempotencyKey {
+					t.Errorf("Idempotency-Key = %q, want %q", got, tt.idempotencyKey)
+				}
+				if atomic.AddInt32(&attempts, 1) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusServiceUnavailable)
+					w.Write([]byte(`{"success": false}`))
+					return
+				}
+				w.Write([]byte(`{"success": true}`))
+			}))
+			defer srv.Close()
+
+			h := NewHttpClient("token")
+			h.SetBaseURL(srv.URL)
+			h.SetRetryPolicy(tt.policy)
+
+			req, err := h.NewRequest(context.Background(), tt.method, "/instances", map[string]string{"a": "b"})
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			if tt.idempotencyKey != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.idempotencyKey)
+			}
+
+			_, err = h.SendRequest(req)
+			var se *StatusError
+			if tt.wantErr != errors.As(err, &se) {
+				t.Errorf("SendRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("SendRequest() attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestHttpClient_RetryGuard(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	tests := []struct {
+		name         string
+		guard        RetryGuard
+		noRetry      bool
+		wantAttempts int32
+		wantBody     string
+		wantStatus   int
+	}{
+		{
+			name:         "sends again when nothing was found",
+			guard:        func(ctx context.Context) (*Response, error) { return nil, nil },
+			wantAttempts: 2,
+			wantBody:     `{"success": true, "data": "posted"}`,
+		},
+		{
+			name:         "not prepared without retries",
+			guard:        func(ctx context.Context) (*Response, error) { return nil, errors.New("unexpected guard call") },
+			noRetry:      true,
+			wantAttempts: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name: "returns the response of the guard",
+			guard: func(ctx context.Context) (*Response, error) {
+				return &Response{StatusCode: http.StatusOK, Body: []byte(`{"success": true, "data": "found"}`)}, nil
+			},
+			wantAttempts: 1,
+			wantBody:     `{"success": true, "data": "found"}`,
+		},
+		{
+			name: "stops retrying when the guard fails",
+			guard: func(ctx context.Context) (*Response, error) {
+				return nil, errors.New("list failed")
+			},
+			wantAttempts: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte(`{"success": true, "data": "posted"}`))
+			}))
+			defer srv.Close()
+
+			h := NewHttpClient("token")
+			h.SetBaseURL(srv.URL)
+			if tt.noRetry {
+				h.SetRetryPolicy(RetryPolicy{})
+			} else {
+				h.SetRetryPolicy(policy)
+			}
+
+			var hooked, prepared int
+			ctx := WithResponseHook(context.Background(), func(*Response) { hooked++ })
+			ctx = WithRetryGuard(ctx, func(ctx context.Context) RetryGuard {
+				prepared++
+				if atomic.LoadInt32(&attempts) != 0 {
+					t.Error("guard prepared after the first attempt")
+				}
+				return func(ctx context.Context) (*Response, error) {
+					if retryGuard(ctx) != nil || len(ctx.Value(responseHooksKey{}).([]func(*Response))) != 0 {
+						t.Error("guard called with the hooks of the request")
+					}
+					return tt.guard(ctx)
+				}
+			})
+
+			req, err := h.NewRequest(ctx, http.MethodPost, "/instances", map[string]string{"a": "b"})
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+			body, err := h.SendRequest(req)
+
+			var se *StatusError
+			if tt.wantStatus != 0 {
+				if !errors.As(err, &se) || se.StatusCode != tt.wantStatus {
+					t.Errorf("SendRequest() error = %v, want status %d", err, tt.wantStatus)
+				}
+			} else if err != nil || string(body) != tt.wantBody {
+				t.Errorf("SendRequest() = %s, %v, want %s", body, err, tt.wantBody)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("SendRequest() attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if hooked != 1 {
+				t.Errorf("response hook called %d times, want 1", hooked)
+			}
+			if wantPrepared := map[bool]int{false: 1, true: 0}[tt.noRetry]; prepared != wantPrepared {
+				t.Errorf("guard prepared %d times, want %d", prepared, wantPrepared)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package letscloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+
+	"github.com/letscloud-community/letscloud-go/domains"
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+// idempotent returns opts preceded by a generated idempotency key, replaced by
+// the key given with WithIdempotencyKey, if any
+func idempotent(opts []RequestOption) []RequestOption {
+	return append([]RequestOption{WithIdempotencyKey(newUUID())}, opts...)
+}
+
+// createdInstance prepares a retry guard looking for the instance created by
+// request, in case an attempt created it although its response was lost and the
+// API ignored the Idempotency-Key header. The instance is recognized by its
+// hostname and label, among the instances that did not exist yet when the guard
+// was prepared, before the first attempt.
+func (c *LetsCloud) createdInstance(request *domains.CreateInstanceRequest) func(context.Context) httpclient.RetryGuard {
+	return reconcile(
+		func(ctx context.Context) iter.Seq2[domains.Instance, error] {
+			return c.ListAllInstances(ctx, ListOptions{})
+		},
+		func(inst domains.Instance) string { return inst.Identifier },
+		func(inst domains.Instance) bool {
+			return inst.Hostname == request.Hostname && inst.Label == request.Label
+		},
+		func(inst domains.Instance) interface{} {
+			out := domains.GetInstanceResponse{Data: inst}
+			out.Success = true
+			return out
+		})
+}
+
+// createdSnapshot returns a retry guard looking for the snapshot labelled label
+// of the instance identifier, see createdInstance
+func (c *LetsCloud) createdSnapshot(label, identifier string) func(context.Context) httpclient.RetryGuard {
+	return reconcile(
+		func(ctx context.Context) iter.Seq2[domains.Snapshot, error] {
+			return c.ListAllSnapshots(ctx, ListOptions{})
+		},
+		func(snap domains.Snapshot) string { return snap.Slug },
+		func(snap domains.Snapshot) bool { return snap.Label == label && snap.Reference == identifier },
+		func(snap domains.Snapshot) interface{} {
+			out := domains.CreateOrGetSnapshotResponse{Data: snap}
+			out.Success = true
+			return out
+		})
+}
+
+// reconcile prepares a retry guard by recording the items listed by list that
+// match. The guard then looks for a matching item missing from that record, so
+// an item that existed before the first attempt is never taken for its outcome,
+// and makes the response of the request of it with wrap. When the record
+// cannot be made, the guard fails, which stops the retries.
+func reconcile[T any](list func(context.Context) iter.Seq2[T, error], id func(T) string, match func(T) bool, wrap func(T) interface{}) func(context.Context) httpclient.RetryGuard {
+	return func(ctx context.Context) httpclient.RetryGuard {
+		existing := make(map[string]bool)
+
+		var listErr error
+		for item, err := range list(ctx) {
+			if err != nil {
+				listErr = err
+				break
+			}
+			if match(item) {
+				existing[id(item)] = true
+			}
+		}
+
+		return func(ctx context.Context) (*httpclient.Response, error) {
+			if listErr != nil {
+				return nil, fmt.Errorf("listing before the first attempt: %w", listErr)
+			}
+
+			for item, err := range list(ctx) {
+				if err != nil {
+					return nil, err
+				}
+				if match(item) && !existing[id(item)] {
+					return reconciled(wrap(item))
+				}
+			}
+
+			return nil, nil
+		}
+	}
+}
+
+// reconciled returns out as the response of a request found to have taken effect
+func reconciled(out interface{}) (*httpclient.Response, error) {
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpclient.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: b}, nil
+}
@@ -0,0 +1,191 @@
+package letscloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/domains"
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+// flakyAPI creates instances and snapshots, failing the first POST with a 503
+// either before or after applying it. Lists are served one item per page.
+type flakyAPI struct {
+	applyFailedPost bool
+
+	mu        sync.Mutex
+	posts     []string
+	walks     int
+	instances []domains.Instance
+	snapshots []domains.Snapshot
+}
+
+func (a *flakyAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if r.Method == http.MethodGet {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page <= 1 {
+			a.walks++
+			page = 1
+		}
+
+		var data interface{} = []domains.Instance{}
+		total := len(a.instances)
+		if r.URL.Path == "/snapshots" {
+			data, total = []domains.Snapshot{}, len(a.snapshots)
+			if page <= total {
+				data = a.snapshots[page-1 : page]
+			}
+		} else if page <= total {
+			data = a.instances[page-1 : page]
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    data,
+			"meta":    domains.PageMeta{CurrentPage: page, PerPage: 1, LastPage: max(total, 1), Total: total},
+		})
+		return
+	}
+
+	a.posts = append(a.posts, r.Header.Get(httpclient.IdempotencyKeyHeader))
+	failed := len(a.posts) == 1
+
+	if !failed || a.applyFailedPost {
+		switch {
+		case r.URL.Path == "/instances":
+			var req domains.CreateInstanceRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			id := fmt.Sprintf("inst%d", len(a.instances)+1)
+			a.instances = append(a.instances, domains.Instance{Identifier: id, Hostname: req.Hostname, Label: req.Label})
+		case strings.HasSuffix(r.URL.Path, "/snapshots"):
+			var req domains.SnapshotCreateRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			slug := fmt.Sprintf("snap%d", len(a.snapshots)+1)
+			ref := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/instances/"), "/snapshots")
+			a.snapshots = append(a.snapshots, domains.Snapshot{Slug: slug, Label: req.Label, Reference: ref})
+		}
+	}
+
+	if failed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte(`{"success": true}`))
+}
+
+func TestClient_CreateInstanceIdempotency(t *testing.T) {
+	policy := httpclient.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	request := &domains.CreateInstanceRequest{
+		LocationSlug: "MIA1",
+		PlanSlug:     "1vcpu-1gb-10ssd",
+		Hostname:     "web.example.com",
+		Label:        "web",
+		ImageSlug:    "ubuntu-24.04-x86_64",
+	}
+
+	tests := []struct {
+		name            string
+		applyFailedPost bool
+		existing        []domains.Instance
+		opts            []RequestOption
+		wantPosts       int
+		wantKey         string
+	}{
+		{
+			name:      "posts again with the same key when nothing was created",
+			wantPosts: 2,
+		},
+		{
+			name:            "finds the instance created by the failed attempt",
+			applyFailedPost: true,
+			wantPosts:       1,
+		},
+		{
+			name:      "key given by the caller",
+			opts:      []RequestOption{WithIdempotencyKey("my-key")},
+			wantPosts: 2,
+			wantKey:   "my-key",
+		},
+		{
+			name:      "an instance existing beforehand is not taken for the created one",
+			existing:  []domains.Instance{{Identifier: "old", Hostname: request.Hostname, Label: request.Label}},
+			wantPosts: 2,
+		},
+		{
+			name:            "finds the created instance on a later page",
+			applyFailedPost: true,
+			existing:        []domains.Instance{{Identifier: "old", Hostname: request.Hostname, Label: request.Label}},
+			wantPosts:       1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &flakyAPI{applyFailedPost: tt.applyFailedPost, instances: tt.existing}
+			srv := httptest.NewServer(api)
+			defer srv.Close()
+
+			c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithRetryPolicy(policy))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if err := c.CreateInstance(request, tt.opts...); err != nil {
+				t.Fatalf("CreateInstance() error = %v", err)
+			}
+
+			// the instances are listed before the first attempt and before the retry
+			if len(api.posts) != tt.wantPosts || len(api.instances) != len(tt.existing)+1 || api.walks != 2 {
+				t.Fatalf("CreateInstance() sent %d posts and %d lists for %d new instances, want %d posts, 2 lists and 1 new instance",
+					len(api.posts), api.walks, len(api.instances)-len(tt.existing), tt.wantPosts)
+			}
+			for _, key := range api.posts {
+				if key == "" || key != api.posts[0] || (tt.wantKey != "" && key != tt.wantKey) {
+					t.Errorf("Idempotency-Key of the posts = %q, want one key", api.posts)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestClient_NewSnapshotIdempotency(t *testing.T) {
+	policy := httpclient.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	api := &flakyAPI{
+		applyFailedPost: true,
+		snapshots:       []domains.Snapshot{{Slug: "snap1", Label: "nightly", Reference: "inst1"}},
+	}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	snap, err := c.NewSnapshot("nightly", "inst1")
+	if err != nil {
+		t.Fatalf("NewSnapshot() error = %v", err)
+	}
+	if snap.Data.Slug != "snap2" || len(api.posts) != 1 || len(api.snapshots) != 2 {
+		t.Errorf("NewSnapshot() = %+v after %d posts, want snap2 after 1 post", snap.Data, len(api.posts))
+	}
+
+	// other POST requests carry no key, so the retry policy leaves them alone
+	if _, err := c.NewSSHKey("laptop", ""); err != nil || len(api.posts) != 2 || api.posts[1] != "" {
+		t.Errorf("NewSSHKey() error = %v after posts %q, want a post without a key", err, api.posts)
+	}
+}
@@ -0,0 +1,67 @@
+package letscloud
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// iniFile holds the sections of an INI file, keys are lower case.
+// Keys found before the first section belong to the "default" section.
+type iniFile map[string]map[string]string
+
+func parseINI(r io.Reader) (iniFile, error) {
+	out := iniFile{}
+	section := "default"
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if out[section] == nil {
+				out[section] = map[string]string{}
+			}
+			continue
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+
+		if out[section] == nil {
+			out[section] = map[string]string{}
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:i]))
+		out[section][key] = strings.Trim(strings.TrimSpace(line[i+1:]), `"`)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func readINI(path string) (iniFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ini, err := parseINI(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return ini, nil
+}
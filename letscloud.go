@@ -1,10 +1,21 @@
 package letscloud
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/letscloud-community/letscloud-go/domains"
 	"github.com/letscloud-community/letscloud-go/httpclient"
 )
 
@@ -13,19 +24,23 @@ const Version = "1.2.0"
 
 // LetsCloud represents a wrapper client for our LetsCloud API
 type LetsCloud struct {
-	debug     bool
-	requester Requester
+	debug      bool
+	requester  Requester
+	logger     *slog.Logger
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	cache      *responseCache
+	userAgent  string
+	onResponse func(*ResponseMeta)
+	flight     *flightGroup
+	err        error
+
+	credentials       []CredentialsProvider
+	credentialsSource string
 }
 
 // Requester defines the API that will be used for sending HTTP Requests to the letscloud API
-type Requester interface {
-	NewRequest(method, url string, data interface{}) (*http.Request, error)
-	SendRequest(req *http.Request) ([]byte, error)
-	SetTimeout(d time.Duration)
-	SetAPIKey(t string)
-	SetBaseURL(url string)
-	APIKey() string
-}
+type Requester = httpclient.Requester
 
 // Option is a function that can be used to set options for the LetsCloud client
 type Option func(*LetsCloud)
@@ -44,26 +59,184 @@ func WithBaseURL(baseURL string) Option {
 	}
 }
 
-// WithDebug enables or disables debug mode
+// WithRetryPolicy retries failed requests according to the given policy.
+// POST requests are only retried when they carry an idempotency key, as
+// CreateInstance and NewSnapshot do, or when the policy sets RetryNonIdempotent.
+//
+// With retries enabled, every CreateInstance and NewSnapshot call first lists
+// all the instances or snapshots of the account, one GET request per page, to
+// tell a resource created by a failed attempt from an existing one. Those
+// requests take rate limiter tokens, but bypass the circuit breaker.
+func WithRetryPolicy(policy httpclient.RetryPolicy) Option {
+	return func(lc *LetsCloud) {
+		lc.requester.SetRetryPolicy(policy)
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second with bursts of up to burst requests.
+// The limit is shared by every goroutine using the client and waits honor the caller's context.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(lc *LetsCloud) {
+		lc.requester.SetRateLimiter(httpclient.NewRateLimiter(rps, burst))
+	}
+}
+
+// WithCircuitBreaker fails requests fast with ErrCircuitOpen, without sending
+// them, while cb is open after too many failures. cb may be shared by clients.
+func WithCircuitBreaker(cb *httpclient.CircuitBreaker) Option {
+	return func(lc *LetsCloud) {
+		lc.requester.SetCircuitBreaker(cb)
+	}
+}
+
+// WithHTTPClient sends every request through the given http.Client,
+// e.g. to configure proxies, connection pooling or instrumentation.
+// Apply WithTimeout after it to override the client's own timeout.
+func WithHTTPClient(cl *http.Client) Option {
+	return func(lc *LetsCloud) {
+		if cl == nil {
+			lc.err = ErrInvalidHttpClient
+			return
+		}
+		lc.requester.SetHTTPClient(cl)
+	}
+}
+
+// WithTransport sends every request through the given http.RoundTripper
+func WithTransport(rt http.RoundTripper) Option {
+	return func(lc *LetsCloud) {
+		if rt == nil {
+			lc.err = ErrInvalidHttpClient
+			return
+		}
+		lc.requester.SetTransport(rt)
+	}
+}
+
+// WithMiddleware appends middlewares wrapping every request sent by the client.
+// They run in the given order, the first one being the outermost. Middlewares
+// always see the response body, which is then buffered instead of decoded as
+// it is read.
+func WithMiddleware(mw ...httpclient.Middleware) Option {
+	return func(lc *LetsCloud) {
+		lc.requester.Use(mw...)
+	}
+}
+
+// WithLogger logs every operation and HTTP attempt through the given handler.
+// Request headers and response bodies are only logged at debug level, with
+// the api-token header and secret fields such as passwords redacted.
+func WithLogger(h slog.Handler) Option {
+	return func(lc *LetsCloud) {
+		lc.logger = slog.New(h)
+		lc.requester.SetLogger(lc.logger)
+	}
+}
+
+// WithKeyRefresher asks fn for a new API key when a request is rejected with
+// 401 Unauthorized, then sends the request once more with that key. Requests
+// failing at the same time share a single call to fn.
+func WithKeyRefresher(fn func(ctx context.Context) (string, error)) Option {
+	return func(lc *LetsCloud) {
+		lc.requester.SetKeyRefresher(fn)
+	}
+}
+
+// WithUserAgent identifies the application in the User-Agent header of every
+// request, ahead of the SDK, e.g. "my-app/2.0 letscloud-go/1.2.0"
+func WithUserAgent(ua string) Option {
+	return func(lc *LetsCloud) {
+		lc.userAgent = ua
+	}
+}
+
+// WithDebug enables or disables debug mode. Without WithLogger, debug mode
+// logs to stderr at debug level.
 func WithDebug(debug bool) Option {
 	return func(lc *LetsCloud) {
 		lc.debug = debug
 	}
 }
 
-// New creates a new instance of LetsCloud with the provided API key and options
+// New creates a new instance of LetsCloud with the provided API key and options.
+// When apiKey is empty, the key is looked up through DefaultCredentialsChain,
+// extended with the providers given to WithCredentials.
 func New(apiKey string, opts ...Option) (*LetsCloud, error) {
 	cl := httpclient.NewHttpClient(apiKey)
 	lc := &LetsCloud{requester: cl}
 
-	// Apply options
-	for _, opt := range opts {
-		opt(lc)
+	if err := lc.apply(opts); err != nil {
+		return nil, err
+	}
+
+	creds, err := DefaultCredentialsChain(apiKey, lc.credentials...).Retrieve(context.Background())
+	if errors.Is(err, ErrNoCredentials) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lc.requester.SetAPIKey(creds.APIKey)
+	lc.credentialsSource = creds.Source
+
+	if lc.logger != nil {
+		lc.logger.Debug("letscloud credentials resolved", slog.String("source", creds.Source))
 	}
 
 	return lc, nil
 }
 
+// Clone returns a client with the settings of c, changed by the given options.
+// Changing the settings of either client afterwards, e.g. with SetTimeout,
+// does not affect the other one. Both share the connection pool and the rate
+// limiter, unless an option such as WithHTTPClient replaces them.
+func (c *LetsCloud) Clone(opts ...Option) (*LetsCloud, error) {
+	lc := &LetsCloud{
+		debug:             c.debug,
+		requester:         c.requester.Clone(),
+		logger:            c.logger,
+		tracer:            c.tracer,
+		propagator:        c.propagator,
+		userAgent:         c.userAgent,
+		onResponse:        c.onResponse,
+		credentials:       c.credentials,
+		credentialsSource: c.credentialsSource,
+	}
+
+	if c.cache != nil {
+		lc.cache = newResponseCache(c.cache.cfg)
+	}
+
+	if c.flight != nil {
+		lc.flight = &flightGroup{}
+	}
+
+	if err := lc.apply(opts); err != nil {
+		return nil, err
+	}
+
+	return lc, nil
+}
+
+// apply applies the options to c
+func (c *LetsCloud) apply(opts []Option) error {
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.err != nil {
+		return c.err
+	}
+
+	if c.debug && c.logger == nil {
+		c.logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		c.requester.SetLogger(c.logger)
+	}
+
+	return nil
+}
+
 // SetTimeout sets timeout for http client
 func (c *LetsCloud) SetTimeout(d time.Duration) error {
 	if d < 0 {
@@ -80,7 +253,8 @@ func (c LetsCloud) APIKey() string {
 	return c.requester.APIKey()
 }
 
-// SetAPIKey sets the API Key
+// SetAPIKey sets the API Key. It can be called while other goroutines use the
+// client, e.g. to rotate the key: requests already sent keep the previous key.
 func (c *LetsCloud) SetAPIKey(ak string) error {
 	if ak == "" {
 		return ErrInvalidToken
@@ -92,12 +266,244 @@ func (c *LetsCloud) SetAPIKey(ak string) error {
 
 	c.requester.SetAPIKey(ak)
 
+	if c.cache != nil {
+		c.cache.invalidate()
+	}
+
+	return nil
+}
+
+// envelope is implemented by every response type embedding domains.CommonResponse
+type envelope interface {
+	Common() *domains.CommonResponse
+}
+
+// rawResponse decodes the data of a response into the value given to Do
+type rawResponse struct {
+	domains.CommonResponse
+	Data interface{} `json:"data"`
+}
+
+// Do sends a request to an endpoint of the LetsCloud API that the SDK does not
+// wrap yet, with the same authentication, retries, logging and error mapping
+// as the other calls. path is relative to the base URL, e.g. "/instances",
+// and body, when not nil, is sent as JSON.
+//
+// The data field of the response is decoded into out, which must be a pointer
+// or nil. When out embeds domains.CommonResponse, the whole response is
+// decoded into it instead. A response whose success flag is false is
+// returned as an *APIError.
+func (c *LetsCloud) Do(ctx context.Context, method, path string, body, out interface{}, opts ...RequestOption) error {
+	if !strings.HasPrefix(path, "/") {
+		return errors.New("please provide a path starting with /")
+	}
+
+	if _, ok := out.(envelope); !ok {
+		out = &rawResponse{Data: out}
+	}
+
+	return c.do(ctx, "Do", method, path, body, out, opts)
+}
+
+// do builds a request bound to ctx, sends it and decodes the response body into out.
+// The operation name is carried by the request context for middlewares, opts
+// are the options of the call and attrs are added to the operation span when
+// tracing is enabled. API failures are returned as *APIError.
+func (c *LetsCloud) do(ctx context.Context, op, method, endpoint string, data, out interface{}, opts []RequestOption, attrs ...attribute.KeyValue) (err error) {
+	ro := newRequestOptions(method, opts)
+
+	ctx, cancel := ro.context(ctx)
+	defer cancel()
+
+	ctx = httpclient.WithOperation(ctx, op)
+
+	ctx, span := c.startSpan(ctx, op, method, endpoint, append(attrs, requestIDAttr(ro.requestID)))
+	defer func() { endSpan(span, err) }()
+
+	if c.logger != nil {
+		start := time.Now()
+		defer func() { c.logOperation(ctx, op, method, endpoint, ro.requestID, err, time.Since(start)) }()
+	}
+
+	// identical GET calls in flight share a single request
+	coalesced := c.flight != nil && method == http.MethodGet && len(ro.header) == 0
+
+	var meta *ResponseMeta
+	if ro.meta != nil || c.onResponse != nil || coalesced {
+		meta = &ResponseMeta{Operation: op, RequestID: ro.requestID}
+		ctx = httpclient.WithResponseHook(ctx, meta.observe)
+
+		start := time.Now()
+		defer func() { c.reportMeta(meta, ro, time.Since(start)) }()
+	}
+
+	cacheable := c.cache != nil && method == http.MethodGet && c.cache.cfg.ttl(op) > 0
+
+	var cached *cacheEntry
+	if cacheable {
+		var fresh bool
+		if cached, fresh = c.cache.get(op, endpoint); fresh {
+			if meta != nil {
+				meta.StatusCode, meta.Cached = http.StatusOK, true
+			}
+			return decodeResponse(cached.body, out)
+		}
+	}
+
+	// fetch sends the request and decodes the response into out, returning the
+	// body unless it was decoded as it was read
+	fetch := func() ([]byte, error) {
+		// responses that are neither cached nor shared are decoded as they are
+		// read, without buffering them, into a fresh value for every attempt
+		var streamed bool
+		var decodeErr error
+		ctx := ctx
+		if !cacheable && !coalesced {
+			ctx = httpclient.WithStreamDecoder(ctx, func(body io.Reader) {
+				fresh := newTarget(out)
+				streamed = true
+				if decodeErr = checkResponse(decodeStream(body, fresh), fresh, nil); decodeErr == nil {
+					setTarget(out, fresh)
+				}
+			})
+		}
+
+		b, header, err := c.send(ctx, method, endpoint, data, ro, cacheable, cached)
+		if err != nil {
+			return nil, err
+		}
+
+		if meta != nil && cached != nil && meta.StatusCode == http.StatusNotModified {
+			meta.Cached = true
+		}
+
+		// a streamed response leaves no body, unlike one reconciled by a retry guard
+		if streamed && b == nil {
+			return nil, decodeErr
+		}
+
+		if err := decodeResponse(b, out); err != nil {
+			return nil, err
+		}
+
+		if cacheable {
+			c.cache.set(op, endpoint, b, header.Get("ETag"))
+		}
+
+		return b, nil
+	}
+
+	if coalesced {
+		return c.flight.do(ctx, endpoint+" "+c.requester.APIKey(), out, meta, fetch)
+	}
+
+	_, err = fetch()
+	return err
+}
+
+// send sends a request with the options ro and returns the response body, along
+// with the response headers when withHeader is set. A 304 answer to the
+// revalidation of cached returns the cached body.
+func (c *LetsCloud) send(ctx context.Context, method, endpoint string, data interface{}, ro *requestOptions, withHeader bool, cached *cacheEntry) ([]byte, http.Header, error) {
+	var header http.Header
+	if withHeader {
+		ctx = httpclient.WithResponseHook(ctx, func(r *httpclient.Response) { header = r.Header })
+	}
+
+	req, err := c.requester.NewRequest(ctx, method, endpoint, data)
+	if err != nil {
+		return nil, nil, wrapError(ErrMakingRequest, err)
+	}
+
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	req.Header.Set("User-Agent", c.userAgentHeader())
+	ro.apply(req)
+
+	if cached != nil && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	b, err := c.requester.SendRequest(req)
+	if err != nil {
+		var se *httpclient.StatusError
+		if errors.As(err, &se) {
+			if cached != nil && se.StatusCode == http.StatusNotModified {
+				return cached.body, se.Header, nil
+			}
+			apiErr := newAPIError(se.StatusCode, se.Header, se.Body)
+			apiErr.RequestID = ro.requestID
+			return nil, nil, apiErr
+		}
+		return nil, nil, wrapError(ErrSendingRequest, fmt.Errorf("request %s: %w", ro.requestID, err))
+	}
+
+	return b, header, nil
+}
+
+// reportMeta hands the metadata of the response of a call to the option and the callback asking for it
+func (c *LetsCloud) reportMeta(meta *ResponseMeta, ro *requestOptions, latency time.Duration) {
+	meta.Latency = latency
+
+	if ro.meta != nil {
+		*ro.meta = *meta
+	}
+
+	if c.onResponse != nil {
+		c.onResponse(meta)
+	}
+}
+
+// userAgentHeader returns the User-Agent of the requests, naming the SDK and its version
+func (c *LetsCloud) userAgentHeader() string {
+	ua := "letscloud-go/" + Version
+	if c.userAgent != "" {
+		ua = c.userAgent + " " + ua
+	}
+
+	return ua
+}
+
+// decodeResponse decodes b into out and checks the success flag of the response
+func decodeResponse(b []byte, out interface{}) error {
+	return checkResponse(processResponse(b, out), out, b)
+}
+
+// checkResponse reports the error met decoding the body b into out, if any, or
+// the rejection of the request when the success flag of the response is false
+func checkResponse(decodeErr error, out interface{}, b []byte) error {
+	if decodeErr != nil {
+		return wrapError(ErrDecodingResponse, decodeErr)
+	}
+
+	if env, ok := out.(envelope); ok && !env.Common().Success {
+		return &APIError{StatusCode: http.StatusOK, Message: env.Common().Message, Body: b}
+	}
+
 	return nil
 }
 
-// debugLog logs debug messages if debug mode is enabled
-func (c *LetsCloud) debugLog(message string) {
-	if c.debug {
-		log.Println("[DEBUG]", message)
+// logOperation logs the outcome of an operation once all of its attempts are done
+func (c *LetsCloud) logOperation(ctx context.Context, op, method, endpoint, requestID string, err error, latency time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("operation", op),
+		slog.String("method", method),
+		slog.String("endpoint", endpoint),
+		slog.String("request_id", requestID),
+		slog.Duration("latency", latency),
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		attrs = append(attrs, slog.Int("status", apiErr.StatusCode))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.logger.LogAttrs(ctx, slog.LevelError, "letscloud operation failed", attrs...)
+		return
 	}
+
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "letscloud operation", attrs...)
 }
@@ -1,7 +1,12 @@
 package letscloud
 
 import (
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -147,6 +152,9 @@ func TestLetsCloud_SetTimeout(t *testing.T) {
 }
 
 func TestNew(t *testing.T) {
+	t.Setenv(EnvAPIKey, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
 	mc := gomock.NewController(t)
 	defer mc.Finish()
 
@@ -194,3 +202,141 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNew_WithHTTPClient(t *testing.T) {
+	var called bool
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true, "data": {"name": "John"}}`)),
+		}, nil
+	})
+
+	tests := []struct {
+		name    string
+		opt     Option
+		wantErr error
+	}{
+		{name: "nil http client", opt: WithHTTPClient(nil), wantErr: ErrInvalidHttpClient},
+		{name: "nil transport", opt: WithTransport(nil), wantErr: ErrInvalidHttpClient},
+		{name: "custom http client", opt: WithHTTPClient(&http.Client{Transport: transport})},
+		{name: "custom transport", opt: WithTransport(transport)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+
+			c, err := New(TEST_API_KEY, tt.opt)
+			if err != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if _, err := c.Profile(); err != nil {
+				t.Fatalf("Profile() error = %v", err)
+			}
+			if !called {
+				t.Errorf("Profile() did not use the custom transport")
+			}
+		})
+	}
+}
+
+func TestLetsCloud_Clone(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]http.Header{}
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		seen[req.Header.Get("X-Client")] = req.Header.Clone()
+		mu.Unlock()
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": true, "data": {"name": "John"}}`)),
+		}, nil
+	})
+
+	parent, err := New(TEST_API_KEY, WithTransport(transport),
+		WithMiddleware(httpclient.HeaderMiddleware("X-Client", "parent")))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	clone, err := parent.Clone(WithMiddleware(httpclient.HeaderMiddleware("X-Client", "clone")))
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+
+	if err := clone.SetAPIKey("clone-key"); err != nil {
+		t.Fatalf("SetAPIKey() error = %v", err)
+	}
+	if got := parent.APIKey(); got != TEST_API_KEY {
+		t.Errorf("parent APIKey() = %v, want %v", got, TEST_API_KEY)
+	}
+
+	if _, err := parent.Profile(); err != nil {
+		t.Fatalf("parent Profile() error = %v", err)
+	}
+	if _, err := clone.Profile(); err != nil {
+		t.Fatalf("clone Profile() error = %v", err)
+	}
+
+	if got := seen["parent"].Get("api-token"); got != TEST_API_KEY {
+		t.Errorf("parent sent api-token %q, want %q", got, TEST_API_KEY)
+	}
+	// the clone inherits the parent middleware, its own one runs last
+	if got := seen["clone"].Get("api-token"); got != "clone-key" {
+		t.Errorf("clone sent api-token %q, want %q", got, "clone-key")
+	}
+	if len(seen) != 2 {
+		t.Errorf("requests seen with X-Client %v, want parent and clone", seen)
+	}
+
+	if _, err := parent.Clone(WithHTTPClient(nil)); err != ErrInvalidHttpClient {
+		t.Errorf("Clone() error = %v, want %v", err, ErrInvalidHttpClient)
+	}
+}
+
+func TestNew_WithCircuitBreaker(t *testing.T) {
+	var hits int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		hits++
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "down"}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	cb := httpclient.NewCircuitBreaker(httpclient.BreakerConfig{MinRequests: 2, CoolDown: time.Minute})
+
+	c, err := New(TEST_API_KEY, WithTransport(transport), WithCircuitBreaker(cb))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Profile(); !errors.Is(err, ErrServer) {
+			t.Fatalf("Profile() error = %v, want %v", err, ErrServer)
+		}
+	}
+
+	if _, err := c.Profile(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Profile() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if hits != 2 {
+		t.Errorf("sent %d requests, want 2", hits)
+	}
+}
@@ -0,0 +1,410 @@
+// Package letscloudtest provides a fake LetsCloud API keeping its state in
+// memory, for end-to-end tests of code built on the SDK:
+//
+//	srv := letscloudtest.NewServer()
+//	defer srv.Close()
+//
+//	client, err := letscloud.New(srv.APIKey(), letscloud.WithBaseURL(srv.URL))
+//
+// Instances go through the building, built and booted states, are locked
+// while an operation runs and snapshots take a while to build. Transitions
+// happen in real time, and Advance skips ahead without waiting.
+package letscloudtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/domains"
+)
+
+// DefaultAPIKey is the API key accepted by a server created without WithAPIKey
+const DefaultAPIKey = "letscloudtest-api-key"
+
+// Fault makes the server answer matching requests with an error
+type Fault struct {
+	// Method and Path select the requests failing, empty values match any request.
+	// Path is compared with the URL path, e.g. "/instances".
+	Method string
+	Path   string
+
+	StatusCode int
+	Message    string
+	// Times is the number of requests failing, zero fails every matching request
+	Times int
+}
+
+// Option is a function that can be used to set options for the Server
+type Option func(*Server)
+
+// WithAPIKey sets the API key expected in the api-token header. An empty key accepts any request.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithBuildTime sets how long a new instance is building, then booting. It defaults to 2s each.
+func WithBuildTime(build, boot time.Duration) Option {
+	return func(s *Server) {
+		s.buildTime = build
+		s.bootTime = boot
+	}
+}
+
+// WithOperationTime sets how long an instance stays locked by a power, reboot or
+// password operation, and how long a snapshot takes to build. They default to 1s and 3s.
+func WithOperationTime(operation, snapshot time.Duration) Option {
+	return func(s *Server) {
+		s.operationTime = operation
+		s.snapshotTime = snapshot
+	}
+}
+
+// Server is a fake LetsCloud API. It implements http.Handler and is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server started by NewServer
+	URL string
+
+	apiKey        string
+	buildTime     time.Duration
+	bootTime      time.Duration
+	operationTime time.Duration
+	snapshotTime  time.Duration
+
+	mux  *http.ServeMux
+	http *httptest.Server
+
+	mu        sync.Mutex
+	offset    time.Duration
+	events    []event
+	faults    []*Fault
+	seq       int
+	profile   domains.Profile
+	locations []*location
+	sshKeys   []*domains.SSHKey
+	instances []*domains.Instance
+	snapshots []*domains.Snapshot
+}
+
+type location struct {
+	domains.Location
+	plans  []domains.Plan
+	images []domains.Image
+}
+
+// event is a state transition applied once its time has come
+type event struct {
+	at    time.Time
+	apply func()
+}
+
+// New creates a server with a profile, a few locations, plans and images, ready
+// to be served, e.g. with http.ListenAndServe
+func New(opts ...Option) *Server {
+	s := &Server{
+		apiKey:        DefaultAPIKey,
+		buildTime:     2 * time.Second,
+		bootTime:      2 * time.Second,
+		operationTime: time.Second,
+		snapshotTime:  3 * time.Second,
+		profile: domains.Profile{
+			Name:        "Jane Doe",
+			CompanyName: "Example Inc",
+			Email:       "jane@example.com",
+			Currency:    "USD",
+			Balance:     "100.00",
+		},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.AddLocation(domains.Location{Slug: "MIA1", Country: "United States", City: "Miami", Available: true}, defaultPlans, defaultImages)
+	s.AddLocation(domains.Location{Slug: "SAO1", Country: "Brazil", City: "Sao Paulo", Available: true}, defaultPlans, defaultImages)
+	s.AddLocation(domains.Location{Slug: "DAL1", Country: "United States", City: "Dallas"}, defaultPlans, defaultImages)
+
+	s.routes()
+
+	return s
+}
+
+// NewServer starts a server on a local port, its base URL is in URL
+func NewServer(opts ...Option) *Server {
+	s := New(opts...)
+	s.http = httptest.NewServer(s)
+	s.URL = s.http.URL
+
+	return s
+}
+
+// Close shuts down a server started by NewServer
+func (s *Server) Close() {
+	if s.http != nil {
+		s.http.Close()
+	}
+}
+
+// APIKey returns the API key expected by the server
+func (s *Server) APIKey() string {
+	return s.apiKey
+}
+
+var defaultPlans = []domains.Plan{
+	{CurrencyCode: "USD", Shortcode: "1vcpu-1gb", Slug: "1vcpu-1gb-10ssd", Core: 1, Memory: 1024, Disk: 10, Bandwidth: 1000, MonthlyValue: "5.00"},
+	{CurrencyCode: "USD", Shortcode: "2vcpu-2gb", Slug: "2vcpu-2gb-20ssd", Core: 2, Memory: 2048, Disk: 20, Bandwidth: 2000, MonthlyValue: "10.00"},
+	{CurrencyCode: "USD", Shortcode: "4vcpu-8gb", Slug: "4vcpu-8gb-80ssd", Core: 4, Memory: 8192, Disk: 80, Bandwidth: 4000, MonthlyValue: "40.00"},
+}
+
+var defaultImages = []domains.Image{
+	{Slug: "ubuntu-24.04-x86_64", Distro: "Ubuntu 24.04", OS: "linux"},
+	{Slug: "debian-12-x86_64", Distro: "Debian 12", OS: "linux"},
+	{Slug: "rocky-9-x86_64", Distro: "Rocky Linux 9", OS: "linux"},
+}
+
+// AddLocation adds a location, or replaces the one with the same slug, along with its plans and images
+func (s *Server) AddLocation(loc domains.Location, plans []domains.Plan, images []domains.Image) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := &location{Location: loc, plans: plans, images: images}
+
+	for i, existing := range s.locations {
+		if existing.Slug == loc.Slug {
+			s.locations[i] = l
+			return
+		}
+	}
+
+	s.locations = append(s.locations, l)
+}
+
+// SetProfile replaces the profile of the account
+func (s *Server) SetProfile(p domains.Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.profile = p
+}
+
+// Advance moves the clock of the server forward, applying the transitions due in the meantime
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.offset += d
+	s.tick()
+}
+
+// Inject makes the server answer the requests matching f with an error
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every fault injected
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Instances returns a copy of the instances of the account
+func (s *Server) Instances() []domains.Instance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tick()
+
+	out := make([]domains.Instance, len(s.instances))
+	for i, inst := range s.instances {
+		out[i] = *inst
+	}
+
+	return out
+}
+
+// Snapshots returns a copy of the snapshots of the account
+func (s *Server) Snapshots() []domains.Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tick()
+
+	out := make([]domains.Snapshot, len(s.snapshots))
+	for i, snap := range s.snapshots {
+		out[i] = *snap
+	}
+
+	return out
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+// schedule applies fn once d has elapsed
+func (s *Server) schedule(d time.Duration, fn func()) {
+	s.events = append(s.events, event{at: s.now().Add(d), apply: fn})
+	sort.SliceStable(s.events, func(i, j int) bool { return s.events[i].at.Before(s.events[j].at) })
+}
+
+// tick applies the transitions due, in order
+func (s *Server) tick() {
+	now := s.now()
+
+	n := 0
+	for n < len(s.events) && !s.events[n].at.After(now) {
+		s.events[n].apply()
+		n++
+	}
+	s.events = s.events[n:]
+}
+
+// ServeHTTP serves the fake API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tick()
+
+	if s.apiKey != "" && r.Header.Get("api-token") != s.apiKey {
+		writeError(w, http.StatusUnauthorized, "Unauthenticated.")
+		return
+	}
+
+	if f := s.fault(r); f != nil {
+		message := f.Message
+		if message == "" {
+			message = http.StatusText(f.StatusCode)
+		}
+		writeError(w, f.StatusCode, message)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// fault returns the fault injected for r, if any
+func (s *Server) fault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || (f.Path != "" && f.Path != r.URL.Path) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+type response struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Meta    *domains.PageMeta `json:"meta,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response{Success: true, Data: data})
+}
+
+// writeList writes the page of items selected by the page and per_page query
+// parameters along with its metadata. Without per_page, every item is written.
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage <= 0 {
+		perPage = max(len(items), 1)
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	page = max(page, 1)
+
+	meta := &domains.PageMeta{
+		CurrentPage: page,
+		PerPage:     perPage,
+		LastPage:    max((len(items)+perPage-1)/perPage, 1),
+		Total:       len(items),
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response{Success: true, Data: items[start:end], Meta: meta})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response{Message: message})
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s%06d", prefix, s.seq)
+}
+
+func (s *Server) location(slug string) *location {
+	for _, l := range s.locations {
+		if l.Slug == slug {
+			return l
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) sshKey(slug string) (int, *domains.SSHKey) {
+	for i, k := range s.sshKeys {
+		if k.Slug == slug || k.Title == slug {
+			return i, k
+		}
+	}
+
+	return -1, nil
+}
+
+func (s *Server) instance(id string) (int, *domains.Instance) {
+	for i, inst := range s.instances {
+		if inst.Identifier == id {
+			return i, inst
+		}
+	}
+
+	return -1, nil
+}
+
+func (s *Server) snapshot(slug string) (int, *domains.Snapshot) {
+	for i, snap := range s.snapshots {
+		if snap.Slug == slug {
+			return i, snap
+		}
+	}
+
+	return -1, nil
+}
+
+// slugify turns a title into a slug, e.g. "My Laptop" into "my-laptop"
+func slugify(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), "-")
+}
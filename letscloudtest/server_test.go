@@ -0,0 +1,219 @@
+package letscloudtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	letscloud "github.com/letscloud-community/letscloud-go"
+	"github.com/letscloud-community/letscloud-go/domains"
+)
+
+func newClient(t *testing.T, srv *Server) *letscloud.LetsCloud {
+	t.Helper()
+
+	c, err := letscloud.New(srv.APIKey(), letscloud.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("letscloud.New() error = %v", err)
+	}
+
+	return c
+}
+
+func TestServer_InstanceLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	c := newClient(t, srv)
+
+	key, err := c.NewSSHKey("My Laptop", "")
+	if err != nil {
+		t.Fatalf("NewSSHKey() error = %v", err)
+	}
+	if key.Slug != "my-laptop" || key.PrivateKey == "" {
+		t.Errorf("NewSSHKey() = %+v, want a generated key pair", key)
+	}
+
+	err = c.CreateInstance(&domains.CreateInstanceRequest{
+		LocationSlug: "MIA1",
+		PlanSlug:     "1vcpu-1gb-10ssd",
+		Hostname:     "web.example.com",
+		Label:        "web",
+		ImageSlug:    "ubuntu-24.04-x86_64",
+		SSHSlug:      key.Slug,
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance() error = %v", err)
+	}
+
+	instances, err := c.Instances()
+	if err != nil || len(instances) != 1 {
+		t.Fatalf("Instances() = %v, %v, want one instance", instances, err)
+	}
+	id := instances[0].Identifier
+
+	state := func(built, booted, locked bool) {
+		t.Helper()
+
+		inst, err := c.Instance(id)
+		if err != nil {
+			t.Fatalf("Instance() error = %v", err)
+		}
+		if inst.Built != built || inst.Booted != booted || inst.Locked != locked {
+			t.Errorf("Instance() built, booted, locked = %v, %v, %v, want %v, %v, %v",
+				inst.Built, inst.Booted, inst.Locked, built, booted, locked)
+		}
+	}
+
+	state(false, false, true)
+	if err := c.PowerOffInstance(id); !errors.Is(err, letscloud.ErrConflict) {
+		t.Errorf("PowerOffInstance() while building error = %v, want %v", err, letscloud.ErrConflict)
+	}
+
+	srv.Advance(2 * time.Second)
+	state(true, false, true)
+
+	srv.Advance(2 * time.Second)
+	state(true, true, false)
+
+	if err := c.PowerOffInstance(id); err != nil {
+		t.Fatalf("PowerOffInstance() error = %v", err)
+	}
+	state(true, true, true)
+
+	srv.Advance(time.Second)
+	state(true, false, false)
+
+	snap, err := c.NewSnapshot("before upgrade", id)
+	if err != nil {
+		t.Fatalf("NewSnapshot() error = %v", err)
+	}
+	if snap.Data.Build || snap.Data.Reference != id {
+		t.Errorf("NewSnapshot() = %+v, want a snapshot building from %s", snap.Data, id)
+	}
+	if err := c.DeleteSnapshot(snap.Data.Slug); !errors.Is(err, letscloud.ErrConflict) {
+		t.Errorf("DeleteSnapshot() while building error = %v, want %v", err, letscloud.ErrConflict)
+	}
+
+	srv.Advance(3 * time.Second)
+
+	got, err := c.Snapshot(snap.Data.Slug)
+	if err != nil || !got.Build {
+		t.Errorf("Snapshot() = %+v, %v, want a built snapshot", got, err)
+	}
+	state(true, false, false)
+
+	if err := c.DeleteSnapshot(snap.Data.Slug); err != nil {
+		t.Errorf("DeleteSnapshot() error = %v", err)
+	}
+	if err := c.DeleteInstance(id); err != nil {
+		t.Errorf("DeleteInstance() error = %v", err)
+	}
+	if _, err := c.Instance(id); !errors.Is(err, letscloud.ErrNotFound) {
+		t.Errorf("Instance() after delete error = %v, want %v", err, letscloud.ErrNotFound)
+	}
+}
+
+func TestServer_Catalog(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	c := newClient(t, srv)
+
+	locations, err := c.Locations()
+	if err != nil || len(locations) != 3 {
+		t.Fatalf("Locations() = %v, %v", locations, err)
+	}
+
+	plans, err := c.LocationPlans("MIA1")
+	if err != nil || len(plans) != len(defaultPlans) {
+		t.Errorf("LocationPlans() = %v, %v", plans, err)
+	}
+
+	images, err := c.LocationImages("SAO1")
+	if err != nil || len(images) != len(defaultImages) {
+		t.Errorf("LocationImages() = %v, %v", images, err)
+	}
+
+	err = c.CreateInstance(&domains.CreateInstanceRequest{
+		LocationSlug: "DAL1",
+		PlanSlug:     "1vcpu-1gb-10ssd",
+		Hostname:     "web.example.com",
+		Label:        "web",
+		ImageSlug:    "ubuntu-24.04-x86_64",
+	})
+	var apiErr *letscloud.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("CreateInstance() in an unavailable location error = %v, want 422", err)
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	c := newClient(t, srv)
+
+	srv.Inject(Fault{Method: http.MethodGet, Path: "/profile", StatusCode: http.StatusServiceUnavailable, Times: 2})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Profile(); !errors.Is(err, letscloud.ErrServer) {
+			t.Errorf("Profile() error = %v, want %v", err, letscloud.ErrServer)
+		}
+	}
+	if _, err := c.Profile(); err != nil {
+		t.Errorf("Profile() after the fault error = %v", err)
+	}
+
+	srv.Inject(Fault{StatusCode: http.StatusTooManyRequests, Message: "Too many requests"})
+	if _, err := c.Locations(); !errors.Is(err, letscloud.ErrRateLimited) {
+		t.Errorf("Locations() error = %v, want %v", err, letscloud.ErrRateLimited)
+	}
+
+	srv.ClearFaults()
+	if _, err := c.Locations(); err != nil {
+		t.Errorf("Locations() after ClearFaults error = %v", err)
+	}
+
+	bad, err := letscloud.New("wrong-key", letscloud.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("letscloud.New() error = %v", err)
+	}
+	if _, err := bad.Profile(); !errors.Is(err, letscloud.ErrUnauthorized) {
+		t.Errorf("Profile() with a wrong key error = %v, want %v", err, letscloud.ErrUnauthorized)
+	}
+}
+
+func TestServer_Pagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	c := newClient(t, srv)
+
+	for _, title := range []string{"one", "two", "three", "four", "five"} {
+		if _, err := c.NewSSHKey(title, ""); err != nil {
+			t.Fatalf("NewSSHKey() error = %v", err)
+		}
+	}
+
+	page, err := c.ListSSHKeys(context.Background(), letscloud.ListOptions{Page: 3, PerPage: 2})
+	if err != nil {
+		t.Fatalf("ListSSHKeys() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Title != "five" || page.Meta.LastPage != 3 || page.Meta.Total != 5 {
+		t.Errorf("ListSSHKeys() = %+v, meta %+v", page.Items, page.Meta)
+	}
+
+	var titles []string
+	for key, err := range c.ListAllSSHKeys(context.Background(), letscloud.ListOptions{PerPage: 2}) {
+		if err != nil {
+			t.Fatalf("ListAllSSHKeys() error = %v", err)
+		}
+		titles = append(titles, key.Title)
+	}
+	if len(titles) != 5 {
+		t.Errorf("ListAllSSHKeys() = %v, want 5 keys", titles)
+	}
+}
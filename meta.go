@@ -0,0 +1,173 @@
+package letscloud
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+// ResponseMeta describes the HTTP response of a call, see WithResponseMeta and WithResponseCallback
+type ResponseMeta struct {
+	// Operation is the name of the SDK operation, e.g. "CreateInstance"
+	Operation string
+	// StatusCode is the HTTP status of the response, 0 when none was received
+	StatusCode int
+	Header     http.Header
+	// RequestID is the X-Request-ID echoed by the API, or the one sent with the request
+	RequestID string
+	// RateLimit is the rate limit state reported by the API, nil when the response has none
+	RateLimit *RateLimit
+	// Deprecation holds the deprecation notices of the endpoint, nil when there are none
+	Deprecation *Deprecation
+	// Latency is the duration of the call, retries included
+	Latency time.Duration
+	// Attempts is the number of times the request was sent
+	Attempts int
+	// Cached reports whether the response came from the cache of the client
+	Cached bool
+	// Shared reports whether the response was received by an identical call
+	// made at the same time, see WithRequestCoalescing
+	Shared bool
+}
+
+// RateLimit is the rate limit state reported by the X-RateLimit-* headers of a response
+type RateLimit struct {
+	// Limit is the number of requests allowed per period, 0 when unknown
+	Limit int
+	// Remaining is the number of requests left in the current period
+	Remaining int
+	// Reset is when the period ends, zero when unknown
+	Reset time.Time
+}
+
+// Deprecation holds the deprecation notices sent by the API with the
+// Deprecation, Sunset, Link and Warning headers of a response
+type Deprecation struct {
+	// Deprecated reports whether the endpoint is deprecated
+	Deprecated bool
+	// Date is when the endpoint was deprecated, zero when unknown
+	Date time.Time
+	// Sunset is when the endpoint stops working, zero when unknown
+	Sunset time.Time
+	// Link points to the documentation of the deprecation, if any
+	Link string
+	// Warnings are the Warning headers of the response, e.g. `299 - "Deprecated API"`
+	Warnings []string
+}
+
+// WithResponseMeta fills meta with the metadata of the response of the call,
+// even when the call fails
+func WithResponseMeta(meta *ResponseMeta) RequestOption {
+	return func(ro *requestOptions) {
+		ro.meta = meta
+	}
+}
+
+// WithResponseCallback calls fn with the metadata of the response of every
+// call once it is done, e.g. to track the rate limit quota
+func WithResponseCallback(fn func(*ResponseMeta)) Option {
+	return func(lc *LetsCloud) {
+		lc.onResponse = fn
+	}
+}
+
+// clone returns a deep copy of m
+func (m *ResponseMeta) clone() *ResponseMeta {
+	c := *m
+	c.Header = m.Header.Clone()
+
+	if m.RateLimit != nil {
+		rl := *m.RateLimit
+		c.RateLimit = &rl
+	}
+
+	if m.Deprecation != nil {
+		d := *m.Deprecation
+		d.Warnings = slices.Clone(d.Warnings)
+		c.Deprecation = &d
+	}
+
+	return &c
+}
+
+// observe records the final response of a request
+func (m *ResponseMeta) observe(resp *httpclient.Response) {
+	m.StatusCode = resp.StatusCode
+	m.Header = resp.Header
+	m.Attempts = resp.Attempts
+
+	if id := resp.Header.Get(RequestIDHeader); id != "" {
+		m.RequestID = id
+	}
+
+	m.RateLimit = parseRateLimit(resp.Header, time.Now())
+	m.Deprecation = parseDeprecation(resp.Header)
+}
+
+func parseRateLimit(h http.Header, now time.Time) *RateLimit {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return nil
+	}
+
+	rl := &RateLimit{Remaining: remaining}
+	rl.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	rl.Reset, _ = httpclient.RateLimitReset(h, now)
+
+	return rl
+}
+
+// parseDeprecation reads the Deprecation header (RFC 9745, either "@<unix
+// time>", an HTTP date or "true"), the Sunset header (RFC 8594), the Link
+// with the deprecation or sunset relation and the Warning headers
+func parseDeprecation(h http.Header) *Deprecation {
+	d := &Deprecation{Warnings: h.Values("Warning")}
+
+	if v := h.Get("Deprecation"); v != "" {
+		d.Deprecated = v != "false"
+
+		if secs, err := strconv.ParseInt(strings.TrimPrefix(v, "@"), 10, 64); err == nil && strings.HasPrefix(v, "@") {
+			d.Date = time.Unix(secs, 0)
+		} else if t, err := http.ParseTime(v); err == nil {
+			d.Date = t
+		}
+	}
+
+	if t, err := http.ParseTime(h.Get("Sunset")); err == nil {
+		d.Sunset = t
+	}
+
+	d.Link = deprecationLink(h.Values("Link"))
+
+	if !d.Deprecated && d.Sunset.IsZero() && len(d.Warnings) == 0 {
+		return nil
+	}
+
+	return d
+}
+
+// deprecationLink returns the target of the first link with the deprecation or sunset relation
+func deprecationLink(links []string) string {
+	for _, header := range links {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				rel := strings.Trim(value, `"`)
+				if strings.EqualFold(key, "rel") && (rel == "deprecation" || rel == "sunset") {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+
+	return ""
+}
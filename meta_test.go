@@ -0,0 +1,131 @@
+package letscloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_ResponseMeta(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		header  map[string]string
+		want    ResponseMeta
+		wantErr bool
+	}{
+		{
+			name:   "plain response",
+			status: http.StatusOK,
+			want:   ResponseMeta{Operation: "Profile", StatusCode: http.StatusOK, RequestID: "req-1", Attempts: 1},
+		},
+		{
+			name:   "rate limit and echoed request ID",
+			status: http.StatusOK,
+			header: map[string]string{
+				"X-Request-ID":          "srv-1",
+				"X-RateLimit-Limit":     "100",
+				"X-RateLimit-Remaining": "42",
+				"X-RateLimit-Reset":     "1800000000",
+			},
+			want: ResponseMeta{
+				Operation:  "Profile",
+				StatusCode: http.StatusOK,
+				RequestID:  "srv-1",
+				RateLimit:  &RateLimit{Limit: 100, Remaining: 42, Reset: time.Unix(1800000000, 0)},
+				Attempts:   1,
+			},
+		},
+		{
+			name:   "deprecation notices",
+			status: http.StatusOK,
+			header: map[string]string{
+				"Deprecation": "@1700000000",
+				"Sunset":      "Sun, 01 Nov 2026 00:00:00 GMT",
+				"Link":        `<https://developers.letscloud.io/changelog>; rel="deprecation"; type="text/html"`,
+				"Warning":     `299 - "Deprecated API"`,
+			},
+			want: ResponseMeta{
+				Operation:  "Profile",
+				StatusCode: http.StatusOK,
+				RequestID:  "req-1",
+				Deprecation: &Deprecation{
+					Deprecated: true,
+					Date:       time.Unix(1700000000, 0),
+					Sunset:     time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC),
+					Link:       "https://developers.letscloud.io/changelog",
+					Warnings:   []string{`299 - "Deprecated API"`},
+				},
+				Attempts: 1,
+			},
+		},
+		{
+			name:    "failed call",
+			status:  http.StatusTooManyRequests,
+			header:  map[string]string{"X-RateLimit-Remaining": "0"},
+			want:    ResponseMeta{Operation: "Profile", StatusCode: http.StatusTooManyRequests, RequestID: "req-1", RateLimit: &RateLimit{}, Attempts: 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"success": true, "data": {"name": "John"}}`))
+			}))
+			defer srv.Close()
+
+			var called []*ResponseMeta
+			c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithResponseCallback(func(m *ResponseMeta) {
+				called = append(called, m)
+			}))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var got ResponseMeta
+			if _, err := c.Profile(WithRequestID("req-1"), WithResponseMeta(&got)); (err != nil) != tt.wantErr {
+				t.Fatalf("Profile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got.Latency <= 0 || got.Header == nil {
+				t.Errorf("ResponseMeta latency = %v, header = %v", got.Latency, got.Header)
+			}
+			got.Latency, got.Header = 0, nil
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResponseMeta = %+v, want %+v", got, tt.want)
+			}
+			if len(called) != 1 || called[0].StatusCode != tt.status {
+				t.Errorf("callback called with %+v", called)
+			}
+		})
+	}
+}
+
+func TestClient_ResponseMetaCached(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true, "data": [{"slug": "MIA1"}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithCache(DefaultCacheConfig()))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i, wantCached := range []bool{false, true} {
+		var meta ResponseMeta
+		if _, err := c.Locations(WithResponseMeta(&meta)); err != nil {
+			t.Fatalf("Locations() error = %v", err)
+		}
+		if meta.Cached != wantCached || meta.StatusCode != http.StatusOK {
+			t.Errorf("call %d: ResponseMeta cached = %v, status = %d, want %v, 200", i, meta.Cached, meta.StatusCode, wantCached)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package letscloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Metrics collects request counts, error counts and latencies of the calls made
+// to the LetsCloud API, labelled by SDK operation. It implements http.Handler,
+// serving the collected metrics in the Prometheus text exposition format.
+type Metrics struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[requestKey]uint64
+	errors    map[errorKey]uint64
+	latencies map[string]*histogram
+}
+
+type requestKey struct {
+	operation string
+	status    string
+}
+
+type errorKey struct {
+	operation string
+	class     string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewMetrics creates a metrics collector. Without buckets DefaultLatencyBuckets is used.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+
+	return &Metrics{
+		buckets:   b,
+		requests:  make(map[requestKey]uint64),
+		errors:    make(map[errorKey]uint64),
+		latencies: make(map[string]*histogram),
+	}
+}
+
+// WithMetrics records every API call made by the client into m
+func WithMetrics(m *Metrics) Option {
+	return func(lc *LetsCloud) {
+		lc.requester.Use(m.Middleware())
+	}
+}
+
+// Middleware returns the middleware recording calls into m
+func (m *Metrics) Middleware() httpclient.Middleware {
+	return func(next httpclient.SendFunc) httpclient.SendFunc {
+		return func(req *http.Request) (*httpclient.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			m.observe(req.Context(), resp, err, time.Since(start))
+			return resp, err
+		}
+	}
+}
+
+func (m *Metrics) observe(ctx context.Context, resp *httpclient.Response, err error, latency time.Duration) {
+	op := httpclient.Operation(ctx)
+	if op == "" {
+		op = "unknown"
+	}
+
+	status := "none"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	class := errorClass(resp, err)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{operation: op, status: status}]++
+
+	if class != "" {
+		m.errors[errorKey{operation: op, class: class}]++
+	}
+
+	h, ok := m.latencies[op]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[op] = h
+	}
+
+	secs := latency.Seconds()
+	for i, le := range m.buckets {
+		if secs <= le {
+			h.counts[i]++
+		}
+	}
+	h.sum += secs
+	h.count++
+}
+
+// errorClass classifies a failed call, returning "" for a successful one
+func errorClass(resp *httpclient.Response, err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case err != nil:
+		if httpclient.IsTemporaryError(err) {
+			return "network"
+		}
+		return "other"
+	case resp == nil:
+		return "other"
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "unauthorized"
+	case resp.StatusCode == http.StatusNotFound:
+		return "not_found"
+	case resp.StatusCode >= 500:
+		return "server"
+	case resp.StatusCode >= 400:
+		return "client"
+	}
+
+	var out struct {
+		Success *bool `json:"success"`
+	}
+	if json.Unmarshal(resp.Body, &out) == nil && out.Success != nil && !*out.Success {
+		return "api"
+	}
+
+	return ""
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format to w
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var sb strings.Builder
+
+	sb.WriteString("# HELP letscloud_requests_total Total number of calls made to the LetsCloud API.\n")
+	sb.WriteString("# TYPE letscloud_requests_total counter\n")
+	reqKeys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		if reqKeys[i].operation != reqKeys[j].operation {
+			return reqKeys[i].operation < reqKeys[j].operation
+		}
+		return reqKeys[i].status < reqKeys[j].status
+	})
+	for _, k := range reqKeys {
+		fmt.Fprintf(&sb, "letscloud_requests_total{operation=\"%s\",status=\"%s\"} %d\n",
+			escapeLabel(k.operation), escapeLabel(k.status), m.requests[k])
+	}
+
+	sb.WriteString("# HELP letscloud_request_errors_total Total number of failed calls to the LetsCloud API by error class.\n")
+	sb.WriteString("# TYPE letscloud_request_errors_total counter\n")
+	errKeys := make([]errorKey, 0, len(m.errors))
+	for k := range m.errors {
+		errKeys = append(errKeys, k)
+	}
+	sort.Slice(errKeys, func(i, j int) bool {
+		if errKeys[i].operation != errKeys[j].operation {
+			return errKeys[i].operation < errKeys[j].operation
+		}
+		return errKeys[i].class < errKeys[j].class
+	})
+	for _, k := range errKeys {
+		fmt.Fprintf(&sb, "letscloud_request_errors_total{operation=\"%s\",class=\"%s\"} %d\n",
+			escapeLabel(k.operation), escapeLabel(k.class), m.errors[k])
+	}
+
+	sb.WriteString("# HELP letscloud_request_duration_seconds Latency of calls to the LetsCloud API, retries included.\n")
+	sb.WriteString("# TYPE letscloud_request_duration_seconds histogram\n")
+	ops := make([]string, 0, len(m.latencies))
+	for op := range m.latencies {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		h := m.latencies[op]
+		label := escapeLabel(op)
+		for i, le := range m.buckets {
+			fmt.Fprintf(&sb, "letscloud_request_duration_seconds_bucket{operation=\"%s\",le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(le, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(&sb, "letscloud_request_duration_seconds_bucket{operation=\"%s\",le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(&sb, "letscloud_request_duration_seconds_sum{operation=\"%s\"} %s\n",
+			label, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&sb, "letscloud_request_duration_seconds_count{operation=\"%s\"} %d\n", label, h.count)
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value as required by the exposition format
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,58 @@
+package letscloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/profile":
+			w.Write([]byte(`{"success": true, "data": {"name": "John"}}`))
+		case "/snapshots/abc":
+			w.Write([]byte(`{"success": false, "message": "Snapshot is still building"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success": false, "message": "Not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	m := NewMetrics(0.5, 1)
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithMetrics(m))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	c.Profile()
+	c.Profile()
+	c.Instance("missing")
+	c.Snapshot("abc")
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE letscloud_requests_total counter\n",
+		`letscloud_requests_total{operation="Profile",status="200"} 2` + "\n",
+		`letscloud_requests_total{operation="Instance",status="404"} 1` + "\n",
+		`letscloud_request_errors_total{operation="Instance",class="not_found"} 1` + "\n",
+		`letscloud_request_errors_total{operation="Snapshot",class="api"} 1` + "\n",
+		"# TYPE letscloud_request_duration_seconds histogram\n",
+		`letscloud_request_duration_seconds_bucket{operation="Profile",le="+Inf"} 2` + "\n",
+		`letscloud_request_duration_seconds_count{operation="Profile"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output does not contain %q:\n%s", want, body)
+		}
+	}
+
+	if strings.Contains(body, `letscloud_request_errors_total{operation="Profile"`) {
+		t.Errorf("successful calls must not be counted as errors:\n%s", body)
+	}
+}
@@ -0,0 +1,154 @@
+package letscloud
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/letscloud-community/letscloud-go/domains"
+)
+
+// ListOptions selects a page of a list. Zero values use the defaults of the API.
+type ListOptions struct {
+	// Page is the number of the page, starting at 1
+	Page int
+	// PerPage is the number of items per page
+	PerPage int
+	// Cursor continues a list from the NextCursor of the previous page, instead of Page
+	Cursor string
+}
+
+func (o ListOptions) query() string {
+	v := url.Values{}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	if o.Cursor != "" {
+		v.Set("cursor", o.Cursor)
+	}
+
+	if len(v) == 0 {
+		return ""
+	}
+
+	return "?" + v.Encode()
+}
+
+// Page holds a page of a list along with its metadata
+type Page[T any] struct {
+	Items []T
+	// Meta is nil when the API answered with the whole list at once
+	Meta *domains.PageMeta
+}
+
+// Next returns the options fetching the page following p, or false when p is the last page
+func (p *Page[T]) Next(opts ListOptions) (ListOptions, bool) {
+	if p.Meta == nil || len(p.Items) == 0 {
+		return opts, false
+	}
+
+	switch {
+	case p.Meta.NextCursor != "":
+		opts.Page, opts.Cursor = 0, p.Meta.NextCursor
+	case p.Meta.CurrentPage < p.Meta.LastPage:
+		opts.Page, opts.Cursor = p.Meta.CurrentPage+1, ""
+	default:
+		return opts, false
+	}
+
+	return opts, true
+}
+
+// listAll walks the pages returned by fetch, starting at opts, one page at a
+// time, sending the request of every page with reqOpts
+func listAll[T any](ctx context.Context, opts ListOptions, reqOpts []RequestOption, fetch func(context.Context, ListOptions, ...RequestOption) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			page, err := fetch(ctx, opts, reqOpts...)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			next, ok := page.Next(opts)
+			if !ok {
+				return
+			}
+			opts = next
+		}
+	}
+}
+
+// ListInstances fetches a page of the instances of the current user
+func (c *LetsCloud) ListInstances(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) (*Page[domains.Instance], error) {
+	var out domains.GetInstancesResponse
+
+	if err := c.do(ctx, "Instances", http.MethodGet, "/instances"+opts.query(), nil, &out, reqOpts); err != nil {
+		return nil, err
+	}
+
+	return &Page[domains.Instance]{Items: out.Data, Meta: out.Meta}, nil
+}
+
+// ListAllInstances iterates over every instance of the current user, fetching
+// the pages lazily from the one selected by opts. Iteration stops at the first
+// error. reqOpts apply to the request of every page, so WithResponseMeta holds
+// the metadata of the last page fetched.
+//
+//	for inst, err := range client.ListAllInstances(ctx, letscloud.ListOptions{PerPage: 50}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(inst.Hostname)
+//	}
+func (c *LetsCloud) ListAllInstances(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) iter.Seq2[domains.Instance, error] {
+	return listAll(ctx, opts, reqOpts, c.ListInstances)
+}
+
+// ListSnapshots fetches a page of the snapshots of the current user
+func (c *LetsCloud) ListSnapshots(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) (*Page[domains.Snapshot], error) {
+	var out domains.GetSnapshotsResponse
+
+	if err := c.do(ctx, "Snapshots", http.MethodGet, "/snapshots"+opts.query(), nil, &out, reqOpts); err != nil {
+		return nil, err
+	}
+
+	return &Page[domains.Snapshot]{Items: out.Data, Meta: out.Meta}, nil
+}
+
+// ListAllSnapshots iterates over every snapshot of the current user, fetching
+// the pages lazily from the one selected by opts. Iteration stops at the first
+// error. reqOpts apply to the request of every page.
+func (c *LetsCloud) ListAllSnapshots(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) iter.Seq2[domains.Snapshot, error] {
+	return listAll(ctx, opts, reqOpts, c.ListSnapshots)
+}
+
+// ListSSHKeys fetches a page of the SSH keys of the current user
+func (c *LetsCloud) ListSSHKeys(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) (*Page[domains.SSHKey], error) {
+	var out domains.GetSSHKeysResponse
+
+	if err := c.do(ctx, "SSHKeys", http.MethodGet, "/sshkeys"+opts.query(), nil, &out, reqOpts); err != nil {
+		return nil, err
+	}
+
+	return &Page[domains.SSHKey]{Items: out.Data, Meta: out.Meta}, nil
+}
+
+// ListAllSSHKeys iterates over every SSH key of the current user, fetching
+// the pages lazily from the one selected by opts. Iteration stops at the first
+// error. reqOpts apply to the request of every page.
+func (c *LetsCloud) ListAllSSHKeys(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) iter.Seq2[domains.SSHKey, error] {
+	return listAll(ctx, opts, reqOpts, c.ListSSHKeys)
+}
@@ -0,0 +1,180 @@
+package letscloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_ListAllInstances(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     map[string]string
+		opts      ListOptions
+		stopAfter int
+		want      []string
+		wantCalls int32
+		wantErr   error
+	}{
+		{
+			name: "page numbers",
+			pages: map[string]string{
+				"page=1&per_page=2": `{"success": true, "data": [{"identifier": "a"}, {"identifier": "b"}], "meta": {"current_page": 1, "per_page": 2, "last_page": 2, "total": 3}}`,
+				"page=2&per_page=2": `{"success": true, "data": [{"identifier": "c"}], "meta": {"current_page": 2, "per_page": 2, "last_page": 2, "total": 3}}`,
+			},
+			opts:      ListOptions{Page: 1, PerPage: 2},
+			want:      []string{"a", "b", "c"},
+			wantCalls: 2,
+		},
+		{
+			name: "cursors",
+			pages: map[string]string{
+				"":           `{"success": true, "data": [{"identifier": "a"}], "meta": {"next_cursor": "abc"}}`,
+				"cursor=abc": `{"success": true, "data": [{"identifier": "b"}], "meta": {"next_cursor": "def"}}`,
+				"cursor=def": `{"success": true, "data": [], "meta": {"next_cursor": "ghi"}}`,
+			},
+			want:      []string{"a", "b"},
+			wantCalls: 3,
+		},
+		{
+			name: "without pagination metadata",
+			pages: map[string]string{
+				"": `{"success": true, "data": [{"identifier": "a"}, {"identifier": "b"}]}`,
+			},
+			want:      []string{"a", "b"},
+			wantCalls: 1,
+		},
+		{
+			name: "stopping early fetches no further page",
+			pages: map[string]string{
+				"per_page=1": `{"success": true, "data": [{"identifier": "a"}], "meta": {"current_page": 1, "per_page": 1, "last_page": 5, "total": 5}}`,
+			},
+			opts:      ListOptions{PerPage: 1},
+			stopAfter: 1,
+			want:      []string{"a"},
+			wantCalls: 1,
+		},
+		{
+			name: "error on a later page",
+			pages: map[string]string{
+				"page=1": `{"success": true, "data": [{"identifier": "a"}], "meta": {"current_page": 1, "last_page": 2}}`,
+			},
+			opts:      ListOptions{Page: 1},
+			want:      []string{"a"},
+			wantCalls: 2,
+			wantErr:   ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				body, ok := tt.pages[r.URL.RawQuery]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(body))
+			}))
+			defer srv.Close()
+
+			c, err := New(TEST_API_KEY, WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var got []string
+			var gotErr error
+			for inst, err := range c.ListAllInstances(context.Background(), tt.opts) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, inst.Identifier)
+				if len(got) == tt.stopAfter {
+					break
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListAllInstances() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(gotErr, tt.wantErr) || (gotErr != nil) != (tt.wantErr != nil) {
+				t.Errorf("ListAllInstances() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("ListAllInstances() made %d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_ListSnapshots(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"success": true, "data": [{"slug": "snap-1"}], "meta": {"current_page": %s, "per_page": 1, "last_page": 4, "total": 4}}`,
+			r.URL.Query().Get("page"))
+	}))
+	defer srv.Close()
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	page, err := c.ListSnapshots(context.Background(), ListOptions{Page: 3, PerPage: 1})
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Meta == nil || page.Meta.CurrentPage != 3 || page.Meta.Total != 4 {
+		t.Errorf("ListSnapshots() = %+v, meta %+v", page.Items, page.Meta)
+	}
+
+	next, ok := page.Next(ListOptions{Page: 3, PerPage: 1})
+	if want := (ListOptions{Page: 4, PerPage: 1}); !ok || next != want {
+		t.Errorf("Next() = %+v, %v, want %+v, true", next, ok, want)
+	}
+}
+
+func TestClient_ListAllRequestOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") != "acme" || r.Header.Get(RequestIDHeader) != "req-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		page := r.URL.Query().Get("page")
+		w.Header().Set("X-RateLimit-Remaining", page)
+		fmt.Fprintf(w, `{"success": true, "data": [{"identifier": "inst-%s"}], "meta": {"current_page": %s, "last_page": 2}}`, page, page)
+	}))
+	defer srv.Close()
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var meta ResponseMeta
+	var got []string
+	for inst, err := range c.ListAllInstances(context.Background(), ListOptions{Page: 1},
+		WithHeader("X-Tenant", "acme"), WithRequestID("req-1"), WithResponseMeta(&meta)) {
+		if err != nil {
+			t.Fatalf("ListAllInstances() error = %v", err)
+		}
+		got = append(got, inst.Identifier)
+	}
+
+	if want := []string{"inst-1", "inst-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAllInstances() = %v, want %v", got, want)
+	}
+	if meta.RateLimit == nil || meta.RateLimit.Remaining != 2 {
+		t.Errorf("ResponseMeta rate limit = %+v, want the one of the last page", meta.RateLimit)
+	}
+}
@@ -0,0 +1,276 @@
+// Package recorder records the interactions of a client with the LetsCloud API
+// into cassette files and replays them, so code built on the SDK can be tested
+// offline and deterministically:
+//
+//	rec, err := recorder.New("testdata/instances.json", recorder.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client, err := letscloud.New(apiKey, letscloud.WithTransport(rec))
+//
+// The api-token header and secret fields such as passwords are scrubbed before
+// a cassette is written.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+// ErrNoInteraction is returned when replaying a request that matches no unused interaction of the cassette
+var ErrNoInteraction = errors.New("recorder: no recorded interaction matches the request")
+
+// Mode selects whether a Recorder talks to the API or replays a cassette
+type Mode int
+
+const (
+	// ModeReplay replays the cassette and never sends requests to the API
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the API and records them, replacing the cassette
+	ModeRecord
+	// ModeAuto replays the cassette when it exists and records it otherwise
+	ModeAuto
+)
+
+// Cassette holds the recorded interactions, in the order they happened
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request sent to the API and the response it got
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Path includes the query string.
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Option is a function that can be used to set options for the Recorder
+type Option func(*Recorder)
+
+// WithTransport sets the transport used to reach the API while recording.
+// It defaults to http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// WithScrubber adds a function removing data from interactions before they are
+// written, on top of the api-token header and secret JSON fields
+func WithScrubber(fn func(*Interaction)) Option {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, fn)
+	}
+}
+
+// Recorder is an http.RoundTripper recording to or replaying from a cassette
+// file. It is safe for concurrent use.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubbers []func(*Interaction)
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates a Recorder for the cassette at path. In ModeAuto the mode becomes
+// ModeReplay when the cassette exists and ModeRecord otherwise.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, transport: http.DefaultTransport}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	if r.mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, fmt.Errorf("recorder: %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode the Recorder runs in, ModeReplay or ModeRecord
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip replays the response recorded for req, or sends req and records its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !matches(in.Request, req, body) {
+			continue
+		}
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewBufferString(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.RequestURI(),
+			Header: httpclient.RedactHeader(req.Header),
+			Body:   scrubBody(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     httpclient.RedactHeader(resp.Header),
+			Body:       scrubBody(respBody),
+		},
+	}
+
+	for _, fn := range r.scrubbers {
+		fn(&in)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Stop writes the cassette when recording. Replaying, it reports the
+// interactions of the cassette that were never replayed.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeReplay {
+		for i, used := range r.used {
+			if !used {
+				in := r.cassette.Interactions[i].Request
+				return fmt.Errorf("recorder: interaction %s %s was not replayed", in.Method, in.Path)
+			}
+		}
+		return nil
+	}
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+// matches reports whether req is the recorded request, comparing the method,
+// the path with its query and the body once scrubbed
+func matches(rec Request, req *http.Request, body []byte) bool {
+	return rec.Method == req.Method &&
+		rec.Path == req.URL.RequestURI() &&
+		rec.Body == scrubBody(body)
+}
+
+// scrubBody replaces the secret fields of a JSON body, other bodies are kept as they are
+func scrubBody(body []byte) string {
+	if !json.Valid(body) {
+		return string(body)
+	}
+
+	return httpclient.RedactBody(body)
+}
+
+// readBody reads the body of req and restores it so req can still be sent
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
@@ -0,0 +1,147 @@
+package recorder
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	letscloud "github.com/letscloud-community/letscloud-go"
+	"github.com/letscloud-community/letscloud-go/domains"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/instances":
+			io.Copy(io.Discard, r.Body)
+			w.Write([]byte(`{"success": true, "data": {"identifier": "abc", "initial_root_password": "s3cret"}}`))
+		case "/profile":
+			w.Write([]byte(`{"success": true, "data": {"name": "John"}}`))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassettes", "instance.json")
+	request := &domains.CreateInstanceRequest{
+		LocationSlug: "MIA1",
+		PlanSlug:     "1vcpu-1gb-10ssd",
+		Hostname:     "web.example.com",
+		Label:        "web",
+		ImageSlug:    "ubuntu-24.04-x86_64",
+		Password:     "Hunter2hunter2",
+	}
+
+	run := func(mode Mode) *Recorder {
+		rec, err := New(path, mode)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		c, err := letscloud.New("secret-token", letscloud.WithBaseURL(srv.URL), letscloud.WithTransport(rec))
+		if err != nil {
+			t.Fatalf("letscloud.New() error = %v", err)
+		}
+
+		if err := c.CreateInstance(request); err != nil {
+			t.Fatalf("CreateInstance() error = %v", err)
+		}
+		p, err := c.Profile()
+		if err != nil {
+			t.Fatalf("Profile() error = %v", err)
+		}
+		if p.Name != "John" {
+			t.Errorf("Profile() name = %v, want John", p.Name)
+		}
+
+		if err := rec.Stop(); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+
+		return rec
+	}
+
+	if rec := run(ModeAuto); rec.Mode() != ModeRecord {
+		t.Errorf("Mode() = %v without a cassette, want ModeRecord", rec.Mode())
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, secret := range []string{"secret-token", "Hunter2hunter2", "s3cret"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, b)
+		}
+	}
+
+	// the API is no longer reachable, every response comes from the cassette
+	srv.Close()
+
+	if rec := run(ModeAuto); rec.Mode() != ModeReplay {
+		t.Errorf("Mode() = %v with a cassette, want ModeReplay", rec.Mode())
+	}
+
+	rec, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	c, err := letscloud.New("secret-token", letscloud.WithBaseURL(srv.URL), letscloud.WithTransport(rec))
+	if err != nil {
+		t.Fatalf("letscloud.New() error = %v", err)
+	}
+
+	other := *request
+	other.Hostname = "db.example.com"
+	if err := c.CreateInstance(&other); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("CreateInstance() with another body error = %v, want %v", err, ErrNoInteraction)
+	}
+
+	if err := rec.Stop(); err == nil {
+		t.Errorf("Stop() reported no unused interaction")
+	}
+}
+
+func TestRecorder_Scrubber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Account", "12345")
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "profile.json")
+
+	rec, err := New(path, ModeRecord, WithScrubber(func(in *Interaction) {
+		in.Response.Header.Del("X-Account")
+	}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	resp, err := (&http.Client{Transport: rec}).Get(srv.URL + "/profile")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	// the live response is left untouched
+	if resp.Header.Get("X-Account") != "12345" {
+		t.Errorf("response header X-Account = %q, want 12345", resp.Header.Get("X-Account"))
+	}
+
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(b), "12345") {
+		t.Errorf("cassette contains the scrubbed header:\n%s", b)
+	}
+}
@@ -0,0 +1,124 @@
+package letscloud
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+// RequestIDHeader is the header carrying the ID of every request sent by the client
+const RequestIDHeader = "X-Request-ID"
+
+// RequestOption is a function that can be used to set options for a single call
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	header         http.Header
+	requestID      string
+	idempotencyKey string
+	timeout        time.Duration
+	meta           *ResponseMeta
+}
+
+// WithHeader sets a header on the request of the call, replacing the value set
+// by the client, e.g. for User-Agent. The api-token header cannot be changed.
+func WithHeader(key, value string) RequestOption {
+	return func(ro *requestOptions) {
+		if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(RequestIDHeader) {
+			ro.requestID = value
+			return
+		}
+
+		if ro.header == nil {
+			ro.header = make(http.Header)
+		}
+		ro.header.Set(key, value)
+	}
+}
+
+// WithRequestID sends id in the X-Request-ID header of the call instead of a
+// generated one, e.g. to correlate it with the request that triggered it
+func WithRequestID(id string) RequestOption {
+	return func(ro *requestOptions) {
+		ro.requestID = id
+	}
+}
+
+// WithIdempotencyKey sends key in the Idempotency-Key header of a POST call,
+// instead of the key generated for CreateInstance and NewSnapshot. Reuse the
+// key when retrying a call that failed, e.g. after a timeout, so the API does
+// not create the resource twice. POST calls carrying a key are retried by the
+// retry policy of the client.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(ro *requestOptions) {
+		ro.idempotencyKey = key
+	}
+}
+
+// WithRequestTimeout bounds the whole call by d, retries included, on top of
+// the deadline of its context and the timeout of the client
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(ro *requestOptions) {
+		ro.timeout = d
+	}
+}
+
+// newRequestOptions applies opts and generates a request ID when none is given.
+// Only POST requests carry an idempotency key.
+func newRequestOptions(method string, opts []RequestOption) *requestOptions {
+	ro := &requestOptions{}
+
+	for _, opt := range opts {
+		opt(ro)
+	}
+
+	if ro.requestID == "" {
+		ro.requestID = newUUID()
+	}
+
+	if method != http.MethodPost {
+		ro.idempotencyKey = ""
+	}
+
+	return ro
+}
+
+// context bounds ctx by the timeout of the call, if any
+func (ro *requestOptions) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ro.timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, ro.timeout)
+}
+
+// apply sets the request ID, the idempotency key and the headers of the call on req
+func (ro *requestOptions) apply(req *http.Request) {
+	for key, values := range ro.header {
+		if key == "Api-Token" {
+			continue
+		}
+		req.Header[key] = values
+	}
+
+	req.Header.Set(RequestIDHeader, ro.requestID)
+
+	if ro.idempotencyKey != "" {
+		req.Header.Set(httpclient.IdempotencyKeyHeader, ro.idempotencyKey)
+	}
+}
+
+// newUUID returns a random UUID (version 4)
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,146 @@
+package letscloud
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_RequestOptions(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	tests := []struct {
+		name       string
+		clientOpts []Option
+		opts       []RequestOption
+		check      func(t *testing.T, h http.Header)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, h http.Header) {
+				if !uuid.MatchString(h.Get(RequestIDHeader)) {
+					t.Errorf("X-Request-ID = %q, want a generated UUID", h.Get(RequestIDHeader))
+				}
+				if h.Get("User-Agent") != "letscloud-go/"+Version {
+					t.Errorf("User-Agent = %q", h.Get("User-Agent"))
+				}
+			},
+		},
+		{
+			name:       "user agent of the application",
+			clientOpts: []Option{WithUserAgent("my-app/2.0")},
+			check: func(t *testing.T, h http.Header) {
+				if h.Get("User-Agent") != "my-app/2.0 letscloud-go/"+Version {
+					t.Errorf("User-Agent = %q", h.Get("User-Agent"))
+				}
+			},
+		},
+		{
+			name: "headers and request ID",
+			opts: []RequestOption{
+				WithHeader("X-Tenant", "acme"),
+				WithHeader("api-token", "stolen"),
+				WithRequestID("req-123"),
+			},
+			check: func(t *testing.T, h http.Header) {
+				if h.Get("X-Tenant") != "acme" || h.Get(RequestIDHeader) != "req-123" {
+					t.Errorf("X-Tenant, X-Request-ID = %q, %q", h.Get("X-Tenant"), h.Get(RequestIDHeader))
+				}
+				if h.Get("api-token") != TEST_API_KEY {
+					t.Errorf("api-token = %q, want the key of the client", h.Get("api-token"))
+				}
+			},
+		},
+		{
+			name: "request ID set as a header",
+			opts: []RequestOption{WithHeader("x-request-id", "req-456"), WithHeader("User-Agent", "custom")},
+			check: func(t *testing.T, h http.Header) {
+				if h.Get(RequestIDHeader) != "req-456" || h.Get("User-Agent") != "custom" {
+					t.Errorf("X-Request-ID, User-Agent = %q, %q", h.Get(RequestIDHeader), h.Get("User-Agent"))
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header
+				w.Write([]byte(`{"success": true, "data": {"name": "John"}}`))
+			}))
+			defer srv.Close()
+
+			c, err := New(TEST_API_KEY, append([]Option{WithBaseURL(srv.URL)}, tt.clientOpts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if _, err := c.Profile(tt.opts...); err != nil {
+				t.Fatalf("Profile() error = %v", err)
+			}
+
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestClient_RequestIDEcho(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success": false, "message": "Instance not found"}`))
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithLogger(slog.NewJSONHandler(&logs, nil)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_, err = c.Instance("abc", WithRequestID("req-789"))
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req-789" {
+		t.Fatalf("Instance() error = %v, want an APIError with the request ID", err)
+	}
+	if !strings.Contains(err.Error(), "req-789") {
+		t.Errorf("Error() = %q, want the request ID", err.Error())
+	}
+	if strings.Count(logs.String(), `"request_id":"req-789"`) != 2 {
+		t.Errorf("logs = %s, want the request ID on the attempt and the operation", logs.String())
+	}
+}
+
+func TestClient_RequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	start := time.Now()
+	err = c.RebootInstanceContext(context.Background(), "abc", WithRequestTimeout(50*time.Millisecond), WithRequestID("req-1"))
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrSendingRequest) {
+		t.Errorf("RebootInstance() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err != nil && !strings.Contains(err.Error(), "req-1") {
+		t.Errorf("Error() = %q, want the request ID", err.Error())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("RebootInstance() returned after %v", elapsed)
+	}
+}
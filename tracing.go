@@ -0,0 +1,122 @@
+package letscloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+const tracerName = "github.com/letscloud-community/letscloud-go"
+
+// WithTracerProvider opens a span named after the SDK operation, e.g.
+// letscloud.CreateInstance, for every call made by the client, and propagates
+// the trace context on the outgoing requests. Spans are exported through
+// whatever exporter tp is configured with.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(lc *LetsCloud) {
+		// clients cloned from a traced client already have the middleware
+		if lc.tracer == nil {
+			lc.requester.Use(tracingMiddleware)
+		}
+		lc.tracer = tp.Tracer(tracerName, trace.WithInstrumentationVersion(Version))
+	}
+}
+
+// WithPropagator sets the propagator injecting the trace context into outgoing requests.
+// It defaults to W3C trace context and baggage.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(lc *LetsCloud) {
+		lc.propagator = p
+	}
+}
+
+type propagatorKey struct{}
+
+// startSpan opens the span of an operation when tracing is enabled. The
+// propagator of c is carried by the returned context, as the tracing
+// middleware may be shared with the clients cloned from c.
+func (c *LetsCloud) startSpan(ctx context.Context, op, method, endpoint string, attrs []attribute.KeyValue) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+
+	ctx = context.WithValue(ctx, propagatorKey{}, c.propagator)
+
+	attrs = append(attrs,
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", endpoint))
+
+	return c.tracer.Start(ctx, "letscloud."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// endSpan records the outcome of an operation and ends its span
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		span.SetAttributes(attribute.Int("http.response.status_code", apiErr.StatusCode))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// tracingMiddleware propagates the trace context with the propagator of the
+// client making the request and records the HTTP status and retry count
+func tracingMiddleware(next httpclient.SendFunc) httpclient.SendFunc {
+	return func(req *http.Request) (*httpclient.Response, error) {
+		ctx := req.Context()
+
+		p, _ := ctx.Value(propagatorKey{}).(propagation.TextMapPropagator)
+		if p == nil {
+			p = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+		}
+		p.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := next(req)
+
+		if span := trace.SpanFromContext(ctx); span.IsRecording() && resp != nil {
+			span.SetAttributes(
+				attribute.Int("http.response.status_code", resp.StatusCode),
+				attribute.Int("letscloud.retry_count", resp.Attempts-1))
+		}
+
+		return resp, err
+	}
+}
+
+func instanceAttr(identifier string) attribute.KeyValue {
+	return attribute.String("letscloud.instance.identifier", identifier)
+}
+
+func locationAttr(slug string) attribute.KeyValue {
+	return attribute.String("letscloud.location.slug", slug)
+}
+
+func snapshotAttr(slug string) attribute.KeyValue {
+	return attribute.String("letscloud.snapshot.slug", slug)
+}
+
+func sshKeyAttr(slug string) attribute.KeyValue {
+	return attribute.String("letscloud.ssh_key.slug", slug)
+}
+
+func requestIDAttr(id string) attribute.KeyValue {
+	return attribute.String("letscloud.request_id", id)
+}
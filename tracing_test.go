@@ -0,0 +1,142 @@
+package letscloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/letscloud-community/letscloud-go/httpclient"
+)
+
+func TestTracing(t *testing.T) {
+	var traceparent string
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success": false, "message": "Instance not found"}`))
+	}))
+	defer srv.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	policy := httpclient.DefaultRetryPolicy()
+	policy.BaseDelay = 0
+
+	c, err := New(TEST_API_KEY, WithBaseURL(srv.URL), WithTracerProvider(tp), WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := c.Instance("abc"); err == nil {
+		t.Fatalf("Instance() error = nil, want not found")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "letscloud.Instance" {
+		t.Errorf("span name = %q, want letscloud.Instance", span.Name)
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("span status = %v, want %v", span.Status.Code, codes.Error)
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+
+	wantAttrs := map[attribute.Key]attribute.Value{
+		"letscloud.instance.identifier": attribute.StringValue("abc"),
+		"http.request.method":           attribute.StringValue(http.MethodGet),
+		"http.response.status_code":     attribute.IntValue(http.StatusNotFound),
+		"letscloud.retry_count":         attribute.IntValue(1),
+	}
+	for k, want := range wantAttrs {
+		if got, ok := attrs[k]; !ok || got != want {
+			t.Errorf("span attribute %s = %v, want %v", k, got.Emit(), want.Emit())
+		}
+	}
+
+	if want := span.SpanContext.TraceID().String(); len(traceparent) < 36 || traceparent[3:35] != want {
+		t.Errorf("traceparent header = %q, want trace id %s", traceparent, want)
+	}
+}
+
+// headerPropagator sets a fixed header, counting its injections
+type headerPropagator struct {
+	value    string
+	injected *int
+}
+
+func (p headerPropagator) Inject(_ context.Context, carrier propagation.TextMapCarrier) {
+	*p.injected++
+	carrier.Set("X-Propagator", p.value)
+}
+
+func (p headerPropagator) Extract(ctx context.Context, _ propagation.TextMapCarrier) context.Context {
+	return ctx
+}
+
+func (p headerPropagator) Fields() []string { return []string{"X-Propagator"} }
+
+func TestTracing_Clone(t *testing.T) {
+	var header []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = append(header, r.Header.Get("X-Propagator"))
+		w.Write([]byte(`{"success": true, "data": {}}`))
+	}))
+	defer srv.Close()
+
+	var parentInjected, cloneInjected int
+	parentSpans, cloneSpans := tracetest.NewInMemoryExporter(), tracetest.NewInMemoryExporter()
+
+	parent, err := New(TEST_API_KEY, WithBaseURL(srv.URL),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(parentSpans))),
+		WithPropagator(headerPropagator{value: "parent", injected: &parentInjected}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	clone, err := parent.Clone(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(cloneSpans))),
+		WithPropagator(headerPropagator{value: "clone", injected: &cloneInjected}))
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+
+	if _, err := clone.Instance("abc"); err != nil {
+		t.Fatalf("clone Instance() error = %v", err)
+	}
+	if _, err := parent.Instance("abc"); err != nil {
+		t.Fatalf("parent Instance() error = %v", err)
+	}
+
+	if len(header) != 2 || header[0] != "clone" || header[1] != "parent" {
+		t.Errorf("X-Propagator headers = %q, want the clone's then the parent's", header)
+	}
+	if cloneInjected != 1 || parentInjected != 1 {
+		t.Errorf("injections = %d by the clone's propagator and %d by the parent's, want 1 each", cloneInjected, parentInjected)
+	}
+	if len(cloneSpans.GetSpans()) != 1 || len(parentSpans.GetSpans()) != 1 {
+		t.Errorf("got %d spans from the clone and %d from the parent, want 1 each",
+			len(cloneSpans.GetSpans()), len(parentSpans.GetSpans()))
+	}
+}
@@ -0,0 +1,269 @@
+package letscloud
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLabelLength is the maximum number of characters of instance, snapshot and SSH key labels
+const MaxLabelLength = 64
+
+// FieldError describes why a field of a request is invalid
+type FieldError struct {
+	// Field is the JSON name of the field, e.g. "hostname"
+	Field   string
+	Message string
+}
+
+// ValidationError lists every invalid field of a request. It matches ErrInvalidRequest with errors.Is.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Message
+	}
+
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether target is ErrInvalidRequest
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// Validate checks a request, such as a domains.CreateInstanceRequest, against
+// the rules of the validate tags of its fields and returns a *ValidationError
+// listing every invalid field. The rules, separated by commas, are:
+//
+//	required      the field must not be empty
+//	omitempty     the other rules are skipped when the field is empty
+//	min=N, max=N  the field must have at least, at most N characters
+//	hostname      an RFC 1123 hostname, e.g. web-1.example.com
+//	label         a label of at most MaxLabelLength characters without control characters
+//	slug          letters, digits, dots, dashes and underscores, e.g. ubuntu-24.04-x86_64
+//	sshkey        an OpenSSH public key, e.g. ssh-ed25519 AAAA... user@host
+//	password      mixes at least three of lowercase, uppercase, digits and symbols
+//	pattern=RE    the field must match the regular expression RE, which ends the tag
+func Validate(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: %T is not a struct", v)
+	}
+
+	rt := rv.Type()
+
+	var errs []FieldError
+	for i := 0; i < rt.NumField(); i++ {
+		tag, ok := rt.Field(i).Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+
+		name := strings.Split(rt.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = rt.Field(i).Name
+		}
+
+		if msg := validateField(rv.Field(i), tag); msg != "" {
+			errs = append(errs, FieldError{Field: name, Message: msg})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+
+	return nil
+}
+
+// validateField returns why f breaks the rules of tag, or "" when it is valid
+func validateField(f reflect.Value, tag string) string {
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "pattern=") {
+			rule, tag = tag, ""
+		} else {
+			rule, tag, _ = strings.Cut(tag, ",")
+		}
+
+		name, arg, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			if f.IsZero() {
+				return "is required"
+			}
+			continue
+		case "omitempty":
+			if f.IsZero() {
+				return ""
+			}
+			continue
+		}
+
+		if f.Kind() != reflect.String {
+			return fmt.Sprintf("has unsupported rule %s for a %s", name, f.Kind())
+		}
+
+		if msg := validateString(f.String(), name, arg); msg != "" {
+			return msg
+		}
+	}
+
+	return ""
+}
+
+func validateString(s, rule, arg string) string {
+	switch rule {
+	case "min", "max":
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Sprintf("has invalid rule %s=%s", rule, arg)
+		}
+		if l := utf8.RuneCountInString(s); rule == "min" && l < n {
+			return fmt.Sprintf("must be at least %d characters long", n)
+		} else if rule == "max" && l > n {
+			return fmt.Sprintf("must be at most %d characters long", n)
+		}
+	case "hostname":
+		if !isHostname(s) {
+			return "must be a valid hostname (RFC 1123), e.g. web-1.example.com"
+		}
+	case "label":
+		if utf8.RuneCountInString(s) > MaxLabelLength {
+			return fmt.Sprintf("must be at most %d characters long", MaxLabelLength)
+		}
+		if strings.IndexFunc(s, unicode.IsControl) >= 0 {
+			return "must not contain control characters"
+		}
+	case "slug":
+		if !slugPattern.MatchString(s) {
+			return "must only contain letters, digits, dots, dashes and underscores"
+		}
+	case "sshkey":
+		if !isSSHPublicKey(s) {
+			return "must be an OpenSSH public key, e.g. ssh-ed25519 AAAA... user@host"
+		}
+	case "password":
+		if passwordClasses(s) < 3 {
+			return "must mix at least three of lowercase letters, uppercase letters, digits and symbols"
+		}
+	case "pattern":
+		re, err := compilePattern(arg)
+		if err != nil {
+			return fmt.Sprintf("has invalid rule pattern=%s", arg)
+		}
+		if !re.MatchString(s) {
+			return "must match " + arg
+		}
+	default:
+		return "has unknown rule " + rule
+	}
+
+	return ""
+}
+
+var slugPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var patterns sync.Map
+
+// compilePattern compiles the regular expression of a pattern rule once
+func compilePattern(expr string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(expr, re)
+
+	return re, nil
+}
+
+// isHostname reports whether s is a hostname as defined by RFC 1123: dot separated
+// labels of 1 to 63 letters, digits and dashes, not starting or ending with a dash
+func isHostname(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+var sshKeyTypes = map[string]bool{
+	"ssh-rsa":                            true,
+	"ssh-dss":                            true,
+	"ssh-ed25519":                        true,
+	"ecdsa-sha2-nistp256":                true,
+	"ecdsa-sha2-nistp384":                true,
+	"ecdsa-sha2-nistp521":                true,
+	"sk-ssh-ed25519@openssh.com":         true,
+	"sk-ecdsa-sha2-nistp256@openssh.com": true,
+}
+
+// isSSHPublicKey reports whether s is a public key in the OpenSSH authorized_keys
+// format, "type base64-blob [comment]", whose blob starts with the same type
+func isSSHPublicKey(s string) bool {
+	fields := strings.Fields(s)
+	if len(fields) < 2 || !sshKeyTypes[fields[0]] {
+		return false
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil || len(blob) < 4 {
+		return false
+	}
+
+	n := binary.BigEndian.Uint32(blob)
+	if uint64(n) > uint64(len(blob)-4) {
+		return false
+	}
+
+	return bytes.Equal(blob[4:4+n], []byte(fields[0]))
+}
+
+// passwordClasses counts the classes of characters used by s among
+// lowercase letters, uppercase letters, digits and symbols
+func passwordClasses(s string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}